@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	asyncNamedExportRegex   = regexp.MustCompile(`(?m)^\s*export\s+async\s+function\s*\*?\s*(` + identPattern + `)`)
+	asyncConstExportRegex   = regexp.MustCompile(`(?m)^\s*export\s+(?:const|let|var)\s+(` + identPattern + `)\s*(?::[^=]*)?=\s*async\b`)
+	asyncDefaultExportRegex = regexp.MustCompile(`(?m)^\s*export\s+default\s+async\b`)
+	asyncLocalRegex         = regexp.MustCompile(`(?m)^\s*(?:async\s+function\s*\*?\s*(` + identPattern + `)|(?:const|let|var)\s+(` + identPattern + `)\s*(?::[^=]*)?=\s*async\b)`)
+)
+
+func asyncExports(content string) map[string]bool {
+	exports := make(map[string]bool)
+	for _, match := range asyncNamedExportRegex.FindAllStringSubmatch(content, -1) {
+		exports[match[1]] = true
+	}
+	for _, match := range asyncConstExportRegex.FindAllStringSubmatch(content, -1) {
+		exports[match[1]] = true
+	}
+	if asyncDefaultExportRegex.MatchString(content) {
+		exports["default"] = true
+	}
+
+	locals := make(map[string]bool)
+	for _, match := range asyncLocalRegex.FindAllStringSubmatch(content, -1) {
+		locals[match[1]+match[2]] = true
+	}
+	if match := defaultIdentRegex.FindStringSubmatch(content); match != nil && locals[match[1]] {
+		exports["default"] = true
+	}
+	for _, match := range exportListRegex.FindAllStringSubmatch(content, -1) {
+		names := parseImportedNames(match[1])
+		for i, exported := range parseNamedSpecifiers(match[1]) {
+			if i < len(names) && locals[names[i]] {
+				exports[exported] = true
+			}
+		}
+	}
+	return exports
+}
+
+func findAsyncComponents(filePath string, lines []string, imports []ImportInfo, resolutions []Resolution, config *Config) []Finding {
+	var findings []Finding
+	for index, imp := range imports {
+		if len(resolutions[index].Paths) == 0 {
+			continue
+		}
+		for i, spec := range imp.Specifiers {
+			if i >= len(imp.Names) || imp.Names[i] == "*" {
+				continue
+			}
+			defining, name := resolveReexportName(resolutions[index].Paths[0], imp.Names[i], config)
+			if isClientFile(defining, config) || isServerActionFile(defining, config) {
+				continue
+			}
+			table := loadExportTable(defining, config)
+			if table == nil || !table.Async[name] {
+				continue
+			}
+
+			tagRegex := regexp.MustCompile(jsxTagPattern(spec))
+			for lineNum, line := range lines {
+				for _, loc := range tagRegex.FindAllStringIndex(line, -1) {
+					findings = append(findings, Finding{
+						File:         filePath,
+						Line:         lineNum + 1,
+						Column:       loc[0] + 1,
+						Content:      line,
+						Rule:         RuleAsyncClientComponent,
+						Severity:     SeverityError,
+						Message:      fmt.Sprintf("client file renders <%s>, an async component defined in %s; async components can only render on the server", spec, displayPath(defining)),
+						Component:    spec,
+						ImportSource: imp.Source,
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
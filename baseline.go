@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const defaultBaselineFile = ".rscboundary-baseline.json"
+
+type Baseline struct {
+	Findings []BaselineFinding `json:"findings"`
+}
+
+type BaselineFinding struct {
+	File        string `json:"file"`
+	Fingerprint string `json:"fingerprint"`
+	Rule        string `json:"rule"`
+	Component   string `json:"component,omitempty"`
+}
+
+func runBaselineCommand(args []string) error {
+	command := newGraphCommand("baseline")
+	output := command.flags.String("o", defaultBaselineFile, "baseline file to write")
+	enable := command.flags.String("enable", "", "comma-separated opt-in rules to enable ("+RuleClientInLoop+", "+RuleServerAction+", "+RuleMissingClientDirective+", "+RuleNonSerializableProp+", "+RuleBrowserAPI+", "+RuleImportCasing+")")
+	command.flags.Parse(args)
+
+	config := DefaultConfig()
+	config.Flow = *command.flow
+	config.Enable = splitList(*enable)
+	roots, _, err := loadScanRoots(*command.path, *command.confPath, isFlagSet(command.flags, "path"), config)
+	if err != nil {
+		return err
+	}
+
+	result, err := scanRoots(roots, *command.verbose)
+	if err != nil {
+		return err
+	}
+
+	if err := writeBaseline(*output, newBaseline(result)); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d findings recorded in %s\n", len(result.Findings), *output)
+	return nil
+}
+
+func newBaseline(result *ScanResult) *Baseline {
+	baseline := &Baseline{Findings: []BaselineFinding{}}
+	for _, f := range result.Findings {
+		baseline.Findings = append(baseline.Findings, BaselineFinding{
+			File:        filepath.ToSlash(f.File),
+			Fingerprint: f.Fingerprint,
+			Rule:        f.Rule,
+			Component:   f.Component,
+		})
+	}
+
+	sort.Slice(baseline.Findings, func(i, j int) bool {
+		a, b := baseline.Findings[i], baseline.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+	return baseline
+}
+
+func writeBaseline(path string, baseline *Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func readBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &baseline, nil
+}
+
+func (b *Baseline) filter(result *ScanResult) int {
+	known := make(map[string]bool)
+	for _, f := range b.Findings {
+		known[f.File+"\x00"+f.Fingerprint] = true
+	}
+
+	var kept []Finding
+	for _, f := range result.Findings {
+		if !known[filepath.ToSlash(f.File)+"\x00"+f.Fingerprint] {
+			kept = append(kept, f)
+		}
+	}
+
+	filtered := len(result.Findings) - len(kept)
+	result.Findings = kept
+	return filtered
+}
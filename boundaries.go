@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Boundary struct {
+	Name       string   `json:"name"`
+	Directives []string `json:"directives"`
+}
+
+func parseBoundaries(boundaries []Boundary) ([]Boundary, error) {
+	seen := make(map[string]bool)
+	parsed := make([]Boundary, 0, len(boundaries))
+	for _, boundary := range boundaries {
+		switch {
+		case boundary.Name == "":
+			return nil, fmt.Errorf("boundaries: missing name")
+		case isKnownRule(boundary.Name, nil):
+			return nil, fmt.Errorf("boundaries: '%s' is a built-in rule", boundary.Name)
+		case seen[boundary.Name]:
+			return nil, fmt.Errorf("boundaries: duplicate name '%s'", boundary.Name)
+		case len(boundary.Directives) == 0:
+			return nil, fmt.Errorf("boundaries: %s: no directives", boundary.Name)
+		}
+		seen[boundary.Name] = true
+
+		var directives []string
+		for _, directive := range boundary.Directives {
+			if strings.HasPrefix(directive, "'") || strings.HasPrefix(directive, `"`) {
+				directives = append(directives, directive)
+				continue
+			}
+			directives = append(directives, "'"+directive+"'", `"`+directive+`"`)
+		}
+		parsed = append(parsed, Boundary{Name: boundary.Name, Directives: directives})
+	}
+	return parsed, nil
+}
+
+func boundaryNames(boundaries []Boundary) []string {
+	names := make([]string, len(boundaries))
+	for i, boundary := range boundaries {
+		names[i] = boundary.Name
+	}
+	return names
+}
+
+func (c *Config) markerConfig(boundary Boundary) *Config {
+	marker := *c
+	marker.Directives = boundary.Directives
+	marker.cache = nil
+	return &marker
+}
+
+func findBoundaryCrossings(filePath string, content []byte, lines []string, imports []ImportInfo, resolutions []Resolution, config *Config) []Finding {
+	var findings []Finding
+	for _, boundary := range config.Boundaries {
+		marker := config.markerConfig(boundary)
+		if hasDirective(bytes.NewReader(content), marker) {
+			continue
+		}
+
+		for index, imp := range imports {
+			paths := resolutions[index].Paths
+			if len(paths) == 0 {
+				continue
+			}
+			for i, spec := range imp.Specifiers {
+				defining := paths[0]
+				if !isClientFile(defining, marker) && i < len(imp.Names) && imp.Names[i] != "*" {
+					defining = resolveReexport(defining, imp.Names[i], config)
+				}
+				if !isClientFile(defining, marker) {
+					continue
+				}
+
+				tagRegex := regexp.MustCompile(jsxTagPattern(spec))
+				for lineNum, line := range lines {
+					for _, loc := range tagRegex.FindAllStringIndex(line, -1) {
+						findings = append(findings, Finding{
+							File:               filePath,
+							Line:               lineNum + 1,
+							Column:             loc[0] + 1,
+							Content:            line,
+							Rule:               boundary.Name,
+							Severity:           SeverityWarning,
+							Message:            fmt.Sprintf("<%s> from '%s' is inside the %s boundary (%s) but this file is not", spec, imp.Source, boundary.Name, boundary.Directives[0]),
+							Component:          spec,
+							ImportSource:       imp.Source,
+							ResolvedClientFile: defining,
+						})
+					}
+				}
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type BoundaryGraph struct {
+	Nodes []BoundaryNode `json:"nodes"`
+	Edges []BoundaryEdge `json:"edges"`
+}
+
+type BoundaryNode struct {
+	File        string `json:"file"`
+	Client      bool   `json:"client"`
+	ClientBytes int64  `json:"clientBytes"`
+}
+
+type BoundaryEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Source string `json:"source"`
+}
+
+func runGraphCommand(args []string) error {
+	command := newGraphCommand("graph")
+	format := command.flags.Lookup("format")
+	format.Usage = "output format (dot, mermaid, json)"
+	format.DefValue = "dot"
+	*command.format = "dot"
+	crossings := command.flags.Bool("crossings", false, "with -format json, print only the server -> client crossings drawn by dot and mermaid")
+	config, graph, err := command.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *command.format == "json" && !*crossings {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(buildModuleGraph(graph, config))
+	}
+
+	boundaries := buildBoundaryGraph(graph, config, *command.verbose)
+
+	switch *command.format {
+	case "dot":
+		return writeBoundaryDOT(os.Stdout, boundaries)
+	case "mermaid":
+		return writeBoundaryMermaid(os.Stdout, boundaries)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(boundaries)
+	default:
+		return fmt.Errorf("unknown format: %s", *command.format)
+	}
+}
+
+func buildBoundaryGraph(graph *ImportGraph, config *Config, verbose bool) *BoundaryGraph {
+	closures := make(map[string]map[string]int64)
+	closure := func(path string) map[string]int64 {
+		if files, ok := closures[path]; ok {
+			return files
+		}
+		files := make(map[string]int64)
+		for _, node := range graph.closure(path, config, verbose) {
+			files[node.Path] = node.Size
+		}
+		closures[path] = files
+		return files
+	}
+
+	paths := make([]string, 0, len(graph.Nodes))
+	for path, node := range graph.Nodes {
+		if node.loaded && !node.IsClient {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	boundaries := &BoundaryGraph{}
+	clients := make(map[string]bool)
+	for _, path := range paths {
+		pulled := make(map[string]int64)
+		var edges []BoundaryEdge
+		for _, edge := range graph.Nodes[path].Imports {
+			for _, target := range boundaryTargets(graph, edge, config) {
+				edges = append(edges, BoundaryEdge{From: displayPath(path), To: displayPath(target.Path), Source: edge.Source})
+				clients[target.Path] = true
+				for file, size := range closure(target.Path) {
+					pulled[file] = size
+				}
+			}
+		}
+		if len(edges) == 0 {
+			continue
+		}
+
+		boundaries.Nodes = append(boundaries.Nodes, BoundaryNode{File: displayPath(path), ClientBytes: sumSizes(pulled)})
+		boundaries.Edges = append(boundaries.Edges, edges...)
+	}
+
+	var clientPaths []string
+	for path := range clients {
+		clientPaths = append(clientPaths, path)
+	}
+	sort.Strings(clientPaths)
+	for _, path := range clientPaths {
+		boundaries.Nodes = append(boundaries.Nodes, BoundaryNode{File: displayPath(path), Client: true, ClientBytes: sumSizes(closure(path))})
+	}
+
+	return boundaries
+}
+
+// boundaryTargets returns the client files an import edge pulls in: the
+// imported file itself, or for a barrel the files that define the imported
+// names, found the same way scanContent follows re-exports.
+func boundaryTargets(graph *ImportGraph, edge GraphEdge, config *Config) []*GraphNode {
+	candidates := []string{edge.Target}
+	for _, name := range edge.Names {
+		if name != "*" {
+			candidates = append(candidates, resolveReexport(edge.Target, name, config))
+		}
+	}
+
+	var targets []*GraphNode
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		target, ok := graph.Nodes[canonicalPath(candidate)]
+		if !ok || seen[target.Path] || !target.loaded || !target.IsClient {
+			continue
+		}
+		seen[target.Path] = true
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+func writeBoundaryDOT(w io.Writer, graph *BoundaryGraph) error {
+	fmt.Fprintln(w, "digraph boundaries {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box];")
+	for _, node := range graph.Nodes {
+		label := fmt.Sprintf("%s\\n%d client bytes", node.File, node.ClientBytes)
+		if node.Client {
+			fmt.Fprintf(w, "  %q [label=\"%s\", style=filled, fillcolor=\"#ffd8a8\"];\n", node.File, label)
+		} else {
+			fmt.Fprintf(w, "  %q [label=\"%s\"];\n", node.File, label)
+		}
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(w, "  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Source)
+	}
+	fmt.Fprintln(w, "}")
+	return nil
+}
+
+func writeBoundaryMermaid(w io.Writer, graph *BoundaryGraph) error {
+	ids := make(map[string]string)
+	fmt.Fprintln(w, "flowchart LR")
+	for i, node := range graph.Nodes {
+		ids[node.File] = fmt.Sprintf("n%d", i)
+		class := ""
+		if node.Client {
+			class = ":::client"
+		}
+		fmt.Fprintf(w, "  %s[\"%s<br/>%d client bytes\"]%s\n", ids[node.File], mermaidEscape(node.File), node.ClientBytes, class)
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(w, "  %s -->|\"%s\"| %s\n", ids[edge.From], mermaidEscape(edge.Source), ids[edge.To])
+	}
+	fmt.Fprintln(w, "  classDef client fill:#ffd8a8,stroke:#e8590c")
+	return nil
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
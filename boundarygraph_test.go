@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildBoundaryGraphFollowsBarrels(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"components/Button.tsx": "'use client'\nexport function Button() { return null }\n",
+		"components/Card.tsx":   "'use client'\nexport default function Card() { return null }\n",
+		"components/Label.tsx":  "export function Label() { return null }\n",
+		"components/index.ts":   "export * from './Button'\nexport { default as Card } from './Card'\nexport { Label } from './Label'\n",
+		"components/Direct.tsx": "'use client'\nexport function Direct() { return null }\n",
+		"app/page.tsx":          "import { Button, Card, Label } from '../components'\nimport { Direct } from '../components/Direct'\nexport default function P() { return <><Button /><Card /><Label /><Direct /></> }\n",
+	})
+	config := DefaultConfig()
+	graph, err := buildImportGraph([]ScanRoot{{Path: root, Config: config}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	boundaries := buildBoundaryGraph(graph, config, false)
+
+	var edges []string
+	for _, edge := range boundaries.Edges {
+		edges = append(edges, edge.From+" -> "+edge.To)
+	}
+	file := func(name string) string {
+		return displayPath(filepath.Join(root, filepath.FromSlash(name)))
+	}
+	want := []string{
+		file("app/page.tsx") + " -> " + file("components/Button.tsx"),
+		file("app/page.tsx") + " -> " + file("components/Card.tsx"),
+		file("app/page.tsx") + " -> " + file("components/Direct.tsx"),
+	}
+	if !reflect.DeepEqual(edges, want) {
+		t.Errorf("edges = %v, want %v", edges, want)
+	}
+
+	var dot, mermaid bytes.Buffer
+	if err := writeBoundaryDOT(&dot, boundaries); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBoundaryMermaid(&mermaid, boundaries); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(dot.String(), " -> "); got != len(want) {
+		t.Errorf("DOT output has %d edges, want %d:\n%s", got, len(want), dot.String())
+	}
+	if got := strings.Count(mermaid.String(), "-->"); got != len(want) {
+		t.Errorf("Mermaid output has %d edges, want %d:\n%s", got, len(want), mermaid.String())
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var browserGlobals = map[string]bool{
+	"window":         true,
+	"document":       true,
+	"localStorage":   true,
+	"sessionStorage": true,
+	"navigator":      true,
+}
+
+var browserFunctions = map[string]bool{
+	"createPortal": true,
+}
+
+func findBrowserAPIs(filePath string, lines []string) []Finding {
+	text := strings.Join(lines, "\n")
+	tokens := tokenize(text)
+
+	declared := make(map[string]bool)
+	for i, t := range tokens {
+		if t.kind == tokenIdent && declarationKeywords[tokenText(tokens, i-1)] {
+			declared[t.text] = true
+		}
+	}
+
+	var findings []Finding
+	report := func(t token, message string) {
+		findings = append(findings, Finding{
+			File:     filePath,
+			Line:     t.line,
+			Column:   t.offset - strings.LastIndex(text[:t.offset], "\n"),
+			Content:  lines[t.line-1],
+			Rule:     RuleBrowserAPI,
+			Severity: SeverityWarning,
+			Message:  message,
+		})
+	}
+
+	for i, t := range tokens {
+		if t.kind != tokenIdent || declared[t.text] || tokenText(tokens, i-1) == "typeof" {
+			continue
+		}
+		next := tokenText(tokens, i+1)
+		switch {
+		case browserGlobals[t.text] && tokenText(tokens, i-1) != "." && (next == "." || next == "["):
+			report(t, fmt.Sprintf("server file references %s, which only exists in the browser", t.text))
+		case browserFunctions[t.text] && next == "(" && !isMemberOf(tokens, i):
+			report(t, fmt.Sprintf("server file calls %s(), which needs a DOM node from the browser", t.text))
+		case browserFunctions[t.text] && next == "(" && tokenText(tokens, i-2) == "ReactDOM":
+			report(t, fmt.Sprintf("server file calls ReactDOM.%s(), which needs a DOM node from the browser", t.text))
+		}
+	}
+	return findings
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheDir    = ".rscboundary-cache"
+	directiveCacheFile = "directives.json"
+	cacheStatsFile     = "stats.json"
+	resultCacheDir     = "results"
+
+	directiveCacheVersion = 4
+)
+
+type Cache struct {
+	Dir        string
+	Directives map[string]DirectiveEntry
+	Files      map[string]FileEntry
+	Stats      CacheStats
+
+	mu      sync.Mutex
+	options map[*Config]string
+	hashMu  sync.Mutex
+	hashes  map[string]string
+}
+
+type DirectiveEntry struct {
+	Version  int    `json:"version"`
+	ModTime  int64  `json:"modTime"`
+	Size     int64  `json:"size"`
+	Hash     string `json:"hash,omitempty"`
+	IsClient bool   `json:"isClient"`
+	LastUsed int64  `json:"lastUsed"`
+}
+
+type CacheStats struct {
+	RunAt      time.Time `json:"runAt"`
+	Hits       int       `json:"hits"`
+	Misses     int       `json:"misses"`
+	FileHits   int       `json:"fileHits"`
+	FileMisses int       `json:"fileMisses"`
+}
+
+func openCache(dir string) (*Cache, error) {
+	cache := &Cache{
+		Dir:        dir,
+		Directives: make(map[string]DirectiveEntry),
+		Files:      make(map[string]FileEntry),
+	}
+
+	if err := readCacheFile(filepath.Join(dir, directiveCacheFile), &cache.Directives); err != nil {
+		return nil, err
+	}
+	if err := readCacheFile(filepath.Join(dir, fileCacheFile), &cache.Files); err != nil {
+		return nil, err
+	}
+	if err := readCacheFile(filepath.Join(dir, cacheStatsFile), &cache.Stats); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+func readCacheFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func writeCacheFile(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (c *Cache) lookupDirective(key, path string, info os.FileInfo, limit int64) (bool, bool) {
+	hash := c.contentHash(path, info, limit)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.Directives[key]
+	if !ok || entry.Version != directiveCacheVersion || entry.ModTime != info.ModTime().UnixNano() || entry.Size != info.Size() || entry.Hash != hash {
+		c.Stats.Misses++
+		return false, false
+	}
+
+	c.Stats.Hits++
+	entry.LastUsed = time.Now().Unix()
+	c.Directives[key] = entry
+	return entry.IsClient, true
+}
+
+func (c *Cache) storeDirective(key, path string, info os.FileInfo, limit int64, isClient bool) {
+	hash := c.contentHash(path, info, limit)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Directives[key] = DirectiveEntry{
+		Version:  directiveCacheVersion,
+		ModTime:  info.ModTime().UnixNano(),
+		Size:     info.Size(),
+		Hash:     hash,
+		IsClient: isClient,
+		LastUsed: time.Now().Unix(),
+	}
+}
+
+func (c *Cache) save() error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+	if err := writeCacheFile(filepath.Join(c.Dir, directiveCacheFile), c.Directives); err != nil {
+		return err
+	}
+	if err := writeCacheFile(filepath.Join(c.Dir, fileCacheFile), c.Files); err != nil {
+		return err
+	}
+	return writeCacheFile(filepath.Join(c.Dir, cacheStatsFile), c.Stats)
+}
+
+func (c *Cache) entryCount() int {
+	return len(c.Directives) + len(c.Files) + len(c.resultEntries())
+}
+
+func (c *Cache) resultEntries() []string {
+	entries, _ := filepath.Glob(filepath.Join(c.Dir, resultCacheDir, "*.json"))
+	return entries
+}
+
+func (c *Cache) prune(maxAge time.Duration) int {
+	cutoff := time.Now().Add(-maxAge).Unix()
+	removed := 0
+
+	for _, entry := range c.resultEntries() {
+		info, err := os.Stat(entry)
+		if err == nil && info.ModTime().Unix() >= cutoff {
+			continue
+		}
+		if os.Remove(entry) == nil {
+			removed++
+		}
+	}
+
+	for key, entry := range c.Directives {
+		path, _, _ := strings.Cut(key, "\x00")
+		info, err := os.Stat(path)
+		stale := err != nil ||
+			entry.Version != directiveCacheVersion ||
+			entry.ModTime != info.ModTime().UnixNano() ||
+			entry.Size != info.Size() ||
+			entry.LastUsed < cutoff
+		if stale {
+			delete(c.Directives, key)
+			removed++
+		}
+	}
+
+	return removed + c.pruneFiles(cutoff)
+}
+
+func gitTreeKey(root string, options interface{}) (string, error) {
+	status, err := exec.Command("git", "-C", root, "status", "--porcelain", "--", ".").Output()
+	if err != nil {
+		return "", fmt.Errorf("git status: %w", err)
+	}
+	if len(bytes.TrimSpace(status)) > 0 {
+		return "", errors.New("working tree has uncommitted changes")
+	}
+
+	tree, err := exec.Command("git", "-C", root, "rev-parse", "HEAD^{tree}").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %w", err)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n%s", strings.TrimSpace(string(tree)), absRoot, optionsJSON)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func loadCachedResult(dir, key string) (*ScanResult, bool) {
+	if key == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, resultCacheDir, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+
+	var result ScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, false
+	}
+
+	now := time.Now()
+	os.Chtimes(filepath.Join(dir, resultCacheDir, key+".json"), now, now)
+	return &result, true
+}
+
+func storeCachedResult(dir, key string, result *ScanResult) error {
+	resultDir := filepath.Join(dir, resultCacheDir)
+	if err := os.MkdirAll(resultDir, 0o755); err != nil {
+		return err
+	}
+	return writeCacheFile(filepath.Join(resultDir, key+".json"), result)
+}
+
+func isClientFile(path string, config *Config) bool {
+	defer config.timings.add(phaseDirectives, time.Now())
+	config.dependsOn(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	key := directiveKey(path, config)
+	if config.directives != nil {
+		if isClient, ok := config.directives.lookup(key, info); ok {
+			return isClient
+		}
+	}
+
+	var isClient bool
+	if config.cache == nil {
+		isClient = fileHasDirective(path, config)
+	} else if cached, ok := config.cache.lookupDirective(key, path, info, config.MaxReadBytes); ok {
+		isClient = cached
+	} else {
+		isClient = fileHasDirective(path, config)
+		config.cache.storeDirective(key, path, info, config.MaxReadBytes, isClient)
+	}
+
+	if config.directives != nil {
+		config.directives.store(key, info, isClient)
+	}
+	return isClient
+}
+
+func runCacheCommand(args []string) error {
+	flags := flag.NewFlagSet("cache", flag.ExitOnError)
+	dir := flags.String("dir", defaultCacheDir, "cache directory")
+	maxAge := flags.Duration("max-age", 30*24*time.Hour, "prune entries not used for this long")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: go-rsc-boundary cache status|clear|prune [flags]\n")
+		flags.PrintDefaults()
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("missing cache action")
+	}
+	action := args[0]
+	flags.Parse(args[1:])
+
+	switch action {
+	case "status":
+		cache, err := openCache(*dir)
+		if err != nil {
+			return err
+		}
+		size, err := dirSize(*dir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("cache: %s\n", *dir)
+		fmt.Printf("size: %d bytes\n", size)
+		fmt.Printf("entries: %d\n", cache.entryCount())
+		if cache.Stats.RunAt.IsZero() {
+			fmt.Printf("last run: none\n")
+			return nil
+		}
+		lookups := cache.Stats.Hits + cache.Stats.Misses
+		rate := 0.0
+		if lookups > 0 {
+			rate = float64(cache.Stats.Hits) / float64(lookups) * 100
+		}
+		fmt.Printf("last run: %s: %d hits, %d misses (%.1f%% hit rate)\n",
+			cache.Stats.RunAt.Format(time.RFC3339), cache.Stats.Hits, cache.Stats.Misses, rate)
+		fmt.Printf("files: %d reused, %d rescanned\n", cache.Stats.FileHits, cache.Stats.FileMisses)
+		return nil
+	case "clear":
+		return os.RemoveAll(*dir)
+	case "prune":
+		cache, err := openCache(*dir)
+		if err != nil {
+			return err
+		}
+		removed := cache.prune(*maxAge)
+		fmt.Printf("pruned %d entries, %d left\n", removed, cache.entryCount())
+		if _, err := os.Stat(*dir); err != nil {
+			return nil
+		}
+		return cache.save()
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown cache action: %s", action)
+	}
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	return size, err
+}
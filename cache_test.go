@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func cachedConfig(t *testing.T, dir string, directives ...string) *Config {
+	t.Helper()
+	cache, err := openCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := DefaultConfig()
+	if len(directives) > 0 {
+		config.Directives = directives
+	}
+	config.cache = cache
+	return config
+}
+
+func saveCache(t *testing.T, config *Config) {
+	t.Helper()
+	if err := config.cache.save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDirectiveCacheKeyedByDirectives(t *testing.T) {
+	root := t.TempDir()
+	cacheDir := t.TempDir()
+	writeFiles(t, root, map[string]string{"dom.tsx": "'use dom'\nexport function D() { return null }\n"})
+	path := filepath.Join(root, "dom.tsx")
+
+	tests := []struct {
+		directives []string
+		want       bool
+	}{
+		{nil, false},
+		{[]string{"'use client'", "'use dom'"}, true},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		config := cachedConfig(t, cacheDir, tt.directives...)
+		if got := isClientFile(path, config); got != tt.want {
+			t.Errorf("isClientFile() with directives %v = %v, want %v", config.Directives, got, tt.want)
+		}
+		saveCache(t, config)
+	}
+}
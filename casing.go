@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type listingMemo struct {
+	mu    sync.Mutex
+	names map[string][]string
+}
+
+func newListingMemo() *listingMemo {
+	return &listingMemo{names: make(map[string][]string)}
+}
+
+func (m *listingMemo) lookup(dir string) []string {
+	if m != nil {
+		m.mu.Lock()
+		names, ok := m.names[dir]
+		m.mu.Unlock()
+		if ok {
+			return names
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+
+	if m != nil {
+		m.mu.Lock()
+		m.names[dir] = names
+		m.mu.Unlock()
+	}
+	return names
+}
+
+func (m *listingMemo) forget() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.names = make(map[string][]string)
+}
+
+func onDiskPath(path, knownDir string, config *Config) (string, bool) {
+	abs := absPath(path)
+	dir := absPath(knownDir)
+	for !isWithin(abs, dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil || rel == "." {
+		return path, false
+	}
+
+	mismatch := false
+	actual := dir
+	for _, segment := range strings.Split(rel, string(filepath.Separator)) {
+		names := config.listings.lookup(actual)
+		name := segment
+		if !containsString(names, segment) {
+			for _, candidate := range names {
+				if strings.EqualFold(candidate, segment) {
+					name = candidate
+					mismatch = true
+					break
+				}
+			}
+		}
+		actual = filepath.Join(actual, name)
+	}
+	return actual, mismatch
+}
+
+func findCasingMismatches(filePath string, lines []string, imports []ImportInfo, resolutions []Resolution, config *Config) []Finding {
+	var findings []Finding
+	for index, imp := range imports {
+		if !resolutions[index].Local {
+			continue
+		}
+		for _, path := range resolutions[index].Paths {
+			actual, mismatch := onDiskPath(path, filepath.Dir(filePath), config)
+			if !mismatch {
+				continue
+			}
+			findings = append(findings, Finding{
+				File:         filePath,
+				Line:         imp.Line,
+				Content:      lines[imp.Line-1],
+				Rule:         RuleImportCasing,
+				Severity:     SeverityWarning,
+				Message:      fmt.Sprintf("import '%s' only resolves on a case-insensitive filesystem; the file on disk is %s", imp.Source, displayPath(actual)),
+				ImportSource: imp.Source,
+			})
+			break
+		}
+	}
+	return findings
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func gitChangedFiles(ref string) (map[string]bool, error) {
+	if err := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run(); err != nil {
+		return nil, fmt.Errorf("%s is not a git revision", ref)
+	}
+
+	diff, err := exec.Command("git", "diff", "--name-only", "-z", "--relative", ref).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+	untracked, err := exec.Command("git", "ls-files", "--others", "--exclude-standard", "-z").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+
+	changed := make(map[string]bool)
+	for _, name := range strings.Split(string(diff)+string(untracked), "\x00") {
+		if name != "" {
+			changed[absPath(name)] = true
+		}
+	}
+	return changed, nil
+}
+
+func scanChanged(roots []ScanRoot, changed map[string]bool, verbose bool) (*ScanResult, error) {
+	stems := changedStems(changed)
+	result := &ScanResult{}
+
+	for _, root := range roots {
+		config := root.Config
+		err := walkSourceFiles(root.Path, config, func(path string) {
+			abs := absPath(path)
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return
+			}
+			if !changed[abs] && !mentionsAny(content, stems) {
+				return
+			}
+
+			file := &ScanResult{Scanned: []string{path}}
+			if err := scanContent(path, content, config, verbose, file); err != nil && verbose {
+				fmt.Fprintf(os.Stderr, "Warning: failed to scan %s: %v\n", path, err)
+			}
+			if !changed[abs] && !dependsOnAny(file.Resolved, changed) {
+				return
+			}
+			if verbose && !changed[abs] {
+				fmt.Fprintf(os.Stderr, "Scanning %s: a dependency changed\n", path)
+			}
+			result.Merge(file)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.Resolved = nil
+	return result, nil
+}
+
+func changedStems(changed map[string]bool) [][]byte {
+	seen := make(map[string]bool)
+	var stems [][]byte
+	for path := range changed {
+		stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if stem == "index" {
+			stem = filepath.Base(filepath.Dir(path))
+		}
+		if stem != "" && !seen[stem] {
+			seen[stem] = true
+			stems = append(stems, []byte(stem))
+		}
+	}
+	return stems
+}
+
+func mentionsAny(content []byte, stems [][]byte) bool {
+	for _, stem := range stems {
+		if bytes.Contains(content, stem) {
+			return true
+		}
+	}
+	return false
+}
+
+func dependsOnAny(resolved []string, changed map[string]bool) bool {
+	for _, path := range resolved {
+		if changed[absPath(path)] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestScanChanged(t *testing.T) {
+	gitFixture(t, map[string]string{
+		"Button.tsx":  "'use client'\nexport function Button() { return null }\n",
+		"Card.tsx":    "'use client'\nexport function Card() { return null }\n",
+		"page.tsx":    "import { Button } from './Button'\nexport default function Page() { return <Button /> }\n",
+		"about.tsx":   "import { Card } from './Card'\nexport default function About() { return <Card /> }\n",
+		"mention.tsx": "// Button\nexport function Mention() { return null }\n",
+	})
+	writeFiles(t, ".", map[string]string{
+		"Button.tsx": "'use client'\nexport function Button() { return 'changed' }\n",
+		"new.tsx":    "import { Card } from './Card'\nexport function New() { return <Card /> }\n",
+	})
+
+	changed, err := gitChangedFiles("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for path := range changed {
+		names = append(names, filepath.Base(path))
+	}
+	sort.Strings(names)
+	if want := []string{"Button.tsx", "new.tsx"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("gitChangedFiles() = %v, want %v", names, want)
+	}
+
+	result, err := scanChanged([]ScanRoot{{Path: ".", Config: DefaultConfig()}}, changed, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scanned []string
+	for _, path := range result.Scanned {
+		scanned = append(scanned, filepath.Base(path))
+	}
+	sort.Strings(scanned)
+	if want := []string{"Button.tsx", "new.tsx", "page.tsx"}; !reflect.DeepEqual(scanned, want) {
+		t.Errorf("scanned %v, want %v", scanned, want)
+	}
+	if len(result.Findings) != 2 {
+		t.Errorf("got %d findings, want 2 (page.tsx and new.tsx)", len(result.Findings))
+	}
+
+	if _, err := gitChangedFiles("no-such-ref"); err == nil {
+		t.Error("gitChangedFiles() with an unknown ref succeeded, want an error")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"path/filepath"
+)
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func writeCheckstyleReport(w io.Writer, result *ScanResult) error {
+	report := checkstyleReport{Version: "4.3"}
+	files := make(map[string]int)
+	for _, f := range result.Findings {
+		name := filepath.ToSlash(displayPath(f.File))
+		index, ok := files[name]
+		if !ok {
+			index = len(report.Files)
+			files[name] = index
+			report.Files = append(report.Files, checkstyleFile{Name: name})
+		}
+		report.Files[index].Errors = append(report.Files[index].Errors, checkstyleError{
+			Line:     f.Line,
+			Column:   f.Column,
+			Severity: f.Severity,
+			Message:  findingMessage(f),
+			Source:   "go-rsc-boundary." + f.Rule,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteCheckstyleReport(t *testing.T) {
+	result := &ScanResult{Findings: []Finding{
+		{File: "app/page.tsx", Line: 3, Column: 7, Rule: RuleClientUsage, Severity: SeverityWarning, Component: "Button", ImportSource: "./Button"},
+		{File: "app/page.tsx", Line: 1, Rule: RuleUnresolvedImport, Severity: SeverityError, Message: "unresolved import './x'"},
+		{File: "app/about/page.tsx", Line: 2, Column: 5, Rule: RuleClientUsage, Severity: SeverityWarning, Component: "Card", ImportSource: "./Card"},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeCheckstyleReport(&buf, result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("report does not start with the XML header:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), `column="0"`) {
+		t.Errorf("report has a zero column:\n%s", buf.String())
+	}
+
+	var report checkstyleReport
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Version != "4.3" || len(report.Files) != 2 {
+		t.Fatalf("checkstyle version %q with %d files, want 4.3 with 2 files", report.Version, len(report.Files))
+	}
+	if report.Files[0].Name != "app/page.tsx" || len(report.Files[0].Errors) != 2 {
+		t.Fatalf("first file %s with %d errors, want app/page.tsx with 2", report.Files[0].Name, len(report.Files[0].Errors))
+	}
+
+	tests := []struct {
+		err  checkstyleError
+		want checkstyleError
+	}{
+		{report.Files[0].Errors[0], checkstyleError{Line: 3, Column: 7, Severity: "warning", Message: "client component <Button> from './Button' rendered in a server file", Source: "go-rsc-boundary." + RuleClientUsage}},
+		{report.Files[0].Errors[1], checkstyleError{Line: 1, Severity: "error", Message: "unresolved import './x'", Source: "go-rsc-boundary." + RuleUnresolvedImport}},
+		{report.Files[1].Errors[0], checkstyleError{Line: 2, Column: 5, Severity: "warning", Message: "client component <Card> from './Card' rendered in a server file", Source: "go-rsc-boundary." + RuleClientUsage}},
+	}
+	for i, tt := range tests {
+		if tt.err != tt.want {
+			t.Errorf("error %d = %+v, want %+v", i, tt.err, tt.want)
+		}
+	}
+}
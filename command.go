@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+type graphCommand struct {
+	flags    *flag.FlagSet
+	path     *string
+	confPath *string
+	format   *string
+	verbose  *bool
+	flow     *bool
+}
+
+func newGraphCommand(name string) *graphCommand {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	return &graphCommand{
+		flags:    flags,
+		path:     flags.String("path", ".", "path to scan"),
+		confPath: flags.String("config", "", "config file (defaults to the nearest "+configFileName+" or rscboundary.yaml above -path)"),
+		format:   flags.String("format", "text", "output format (text, json)"),
+		verbose:  flags.Bool("v", false, "verbose output"),
+		flow:     flags.Bool("flow", false, "strip Flow type declarations before extracting imports"),
+	}
+}
+
+func (c *graphCommand) parse(args []string) (*Config, *ImportGraph, error) {
+	c.flags.Parse(args)
+
+	config := DefaultConfig()
+	config.Flow = *c.flow
+	roots, _, err := loadScanRoots(*c.path, *c.confPath, isFlagSet(c.flags, "path"), config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	graph, err := buildImportGraph(roots, *c.verbose)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, graph, nil
+}
+
+func (c *graphCommand) write(w io.Writer, v interface{}, text func(io.Writer) error) error {
+	switch *c.format {
+	case "text":
+		return text(w)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	default:
+		return fmt.Errorf("unknown format: %s", *c.format)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import "strings"
+
+type componentReference struct {
+	line      int
+	column    int
+	endColumn int
+	component string
+}
+
+var arrayPrecedingKeywords = map[string]bool{
+	"return": true,
+	"yield":  true,
+	"await":  true,
+	"case":   true,
+	"in":     true,
+	"of":     true,
+}
+
+func findComponentReferences(text string, components map[string]clientImport) []componentReference {
+	tokens := tokenize(text)
+
+	var refs []componentReference
+	for i, t := range tokens {
+		if t.kind != tokenIdent || isMemberOf(tokens, i) {
+			continue
+		}
+		name, last := t.text, i
+		if tokenText(tokens, i+1) == "." {
+			if _, ok := components[name+"."+tokenText(tokens, i+2)]; ok {
+				name, last = name+"."+tokenText(tokens, i+2), i+2
+			}
+		}
+		if _, ok := components[name]; !ok {
+			continue
+		}
+		if isCreateElementArgument(tokens, i, last) || isArrayElement(tokens, i, last) {
+			refs = append(refs, componentReference{
+				line:      t.line,
+				column:    t.offset - strings.LastIndex(text[:t.offset], "\n"),
+				endColumn: tokens[last].offset + len(tokens[last].text) - strings.LastIndex(text[:t.offset], "\n"),
+				component: name,
+			})
+		}
+	}
+	return refs
+}
+
+func isCreateElementArgument(tokens []token, first, last int) bool {
+	next := tokenText(tokens, last+1)
+	return tokenText(tokens, first-1) == "(" && tokenText(tokens, first-2) == "createElement" && (next == "," || next == ")")
+}
+
+func isArrayElement(tokens []token, first, last int) bool {
+	prev, next := tokenText(tokens, first-1), tokenText(tokens, last+1)
+	if (prev != "[" && prev != ",") || (next != "," && next != "]") {
+		return false
+	}
+
+	depth := 0
+	for i := first - 1; i >= 0; i-- {
+		switch tokens[i].text {
+		case ")", "]", "}":
+			depth++
+		case "(", "{":
+			if depth == 0 {
+				return false
+			}
+			depth--
+		case "[":
+			if depth > 0 {
+				depth--
+				continue
+			}
+			before := i - 1
+			if before < 0 {
+				return true
+			}
+			switch tokens[before].kind {
+			case tokenIdent:
+				return arrayPrecedingKeywords[tokens[before].text]
+			case tokenString, tokenTemplate, tokenNumber:
+				return false
+			}
+			return tokens[before].text != ")" && tokens[before].text != "]"
+		}
+	}
+	return false
+}
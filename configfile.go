@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configFileName = ".rscboundary.json"
+
+var configFileNames = []string{configFileName, "rscboundary.yaml", "rscboundary.yml"}
+
+type FileConfig struct {
+	Path           string                `json:"-"`
+	Directives     []string              `json:"directives"`
+	Extensions     []string              `json:"extensions"`
+	Ignore         []string              `json:"ignore"`
+	Aliases        map[string]string     `json:"aliases"`
+	Format         string                `json:"format"`
+	Framework      string                `json:"framework"`
+	Roots          []RootConfig          `json:"roots"`
+	Budgets        map[string]PathBudget `json:"budgets"`
+	Limits         *TraversalLimits      `json:"limits"`
+	Rules          map[string]string     `json:"rules"`
+	Boundaries     []Boundary            `json:"boundaries"`
+	ClientPackages []string              `json:"clientPackages"`
+	ImportMap      string                `json:"importMap"`
+
+	severities map[string]string
+}
+
+type RootConfig struct {
+	Path       string   `json:"path"`
+	Extensions []string `json:"extensions"`
+	Directives []string `json:"directives"`
+	Ignore     []string `json:"ignore"`
+	Generated  []string `json:"generated"`
+}
+
+type PathBudget struct {
+	MaxClientComponents int `json:"maxClientComponents"`
+}
+
+type ScanRoot struct {
+	Path   string
+	Config *Config
+}
+
+func loadFileConfig(path, scanPath string) (*FileConfig, error) {
+	if path == "" {
+		path = findUp(absPath(scanPath), configFileNames...)
+		if path == "" {
+			return nil, nil
+		}
+		path = displayPath(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(document); err != nil {
+			return nil, err
+		}
+	} else {
+		data = normalizeJSONC(data)
+	}
+
+	fileConfig := &FileConfig{Path: path}
+	if err := json.Unmarshal(data, fileConfig); err != nil {
+		return nil, err
+	}
+	if fileConfig.Boundaries, err = parseBoundaries(fileConfig.Boundaries); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if fileConfig.severities, err = parseRuleSeverities(fileConfig.Rules, fileConfig.Boundaries); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return fileConfig, nil
+}
+
+func loadScanRoots(path, configPath string, explicitPath bool, config *Config) ([]ScanRoot, *FileConfig, error) {
+	fileConfig, err := loadFileConfig(configPath, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if fileConfig != nil {
+		fileConfig.apply(config)
+	}
+
+	if fileConfig != nil && len(fileConfig.Roots) > 0 && !explicitPath {
+		return fileConfig.scanRoots(config), fileConfig, nil
+	}
+	return []ScanRoot{{Path: path, Config: config}}, fileConfig, nil
+}
+
+func (f *FileConfig) apply(config *Config) {
+	if len(f.Directives) > 0 {
+		config.Directives = f.Directives
+	}
+	if len(f.Extensions) > 0 {
+		config.setExtensions(f.Extensions)
+	}
+	config.Ignore = append(config.Ignore, f.Ignore...)
+	if f.Framework != "" && config.Framework == "" {
+		config.Framework = f.Framework
+	}
+	if f.ImportMap != "" && config.ImportMap == "" {
+		config.ImportMap = f.ImportMap
+		if !filepath.IsAbs(config.ImportMap) {
+			config.ImportMap = filepath.Join(filepath.Dir(f.Path), f.ImportMap)
+		}
+	}
+
+	dir := absPath(filepath.Dir(f.Path))
+	for pattern, target := range f.Aliases {
+		config.Aliases = append(config.Aliases, newPathAlias(pattern, target, dir))
+	}
+	sort.Slice(config.Aliases, func(i, j int) bool {
+		return config.Aliases[i].Pattern < config.Aliases[j].Pattern
+	})
+
+	if f.Limits != nil {
+		config.Limits.merge(f.Limits)
+	}
+	if len(f.severities) > 0 {
+		config.Severities = f.severities
+	}
+	config.Boundaries = f.Boundaries
+	config.ClientPackages = append(config.ClientPackages, f.ClientPackages...)
+}
+
+// setExtensions replaces the scanned extensions with extensions from a config
+// file, keeping the ones enabled by flags such as -mdx.
+func (c *Config) setExtensions(extensions []string) {
+	c.SearchExtensions = append([]string{}, extensions...)
+	for _, ext := range c.ExtraExtensions {
+		if !containsString(c.SearchExtensions, ext) {
+			c.SearchExtensions = append(c.SearchExtensions, ext)
+		}
+	}
+}
+
+func (c *Config) isClientPackage(name string) bool {
+	for _, pattern := range c.ClientPackages {
+		if matchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FileConfig) scanRoots(base *Config) []ScanRoot {
+	var roots []ScanRoot
+	dir := filepath.Dir(f.Path)
+
+	for _, root := range f.Roots {
+		config := *base
+		if len(root.Extensions) > 0 {
+			config.setExtensions(root.Extensions)
+		}
+		if len(root.Directives) > 0 {
+			config.Directives = root.Directives
+		}
+		if len(root.Generated) > 0 {
+			config.GeneratedMarkers = append(append([]string{}, base.GeneratedMarkers...), root.Generated...)
+		}
+		config.Ignore = append(append([]string{}, base.Ignore...), root.Ignore...)
+
+		rootPath := root.Path
+		if !filepath.IsAbs(rootPath) {
+			rootPath = filepath.Join(dir, rootPath)
+		}
+
+		roots = append(roots, ScanRoot{Path: rootPath, Config: &config})
+	}
+
+	return roots
+}
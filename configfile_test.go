@@ -0,0 +1,73 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileConfigExtensionsKeepFlagExtensions(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		configFileName: `{"extensions": [".tsx", ".ts"], "roots": [{"path": "docs", "extensions": [".jsx"]}]}`,
+	})
+
+	tests := []struct {
+		name     string
+		extra    []string
+		explicit bool
+		want     []string
+	}{
+		{name: "config only", explicit: true, want: []string{".tsx", ".ts"}},
+		{name: "with -mdx", extra: []string{".mdx"}, explicit: true, want: []string{".tsx", ".ts", ".mdx"}},
+		{name: "root extensions with -mdx", extra: []string{".mdx"}, want: []string{".jsx", ".mdx"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.ExtraExtensions = tt.extra
+			config.setExtensions(config.SearchExtensions)
+
+			roots, _, err := loadScanRoots(root, filepath.Join(root, configFileName), tt.explicit, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := roots[0].Config.SearchExtensions; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchExtensions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileConfigCamelCaseKeys(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		configFileName: `{
+			"limits": {"maxClosureDepth": 8, "maxReexportHops": 2, "cycles": "warn"},
+			"budgets": {"app/**": {"maxClientComponents": 3}}
+		}`,
+		"rscboundary.yaml": "limits:\n  maxClosureDepth: 4\n  maxReexportHops: 1\n",
+	})
+
+	tests := []struct {
+		file   string
+		limits TraversalLimits
+		budget int
+	}{
+		{file: configFileName, limits: TraversalLimits{MaxClosureDepth: 8, MaxReexportHops: 2, Cycles: CyclesWarn}, budget: 3},
+		{file: "rscboundary.yaml", limits: TraversalLimits{MaxClosureDepth: 4, MaxReexportHops: 1, Cycles: CyclesIgnore}},
+	}
+	for _, tt := range tests {
+		config := DefaultConfig()
+		_, fileConfig, err := loadScanRoots(root, filepath.Join(root, tt.file), true, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Limits != tt.limits {
+			t.Errorf("%s: Limits = %+v, want %+v", tt.file, config.Limits, tt.limits)
+		}
+		if got := fileConfig.Budgets["app/**"].MaxClientComponents; got != tt.budget {
+			t.Errorf("%s: budget = %d, want %d", tt.file, got, tt.budget)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const defaultDaemonSocket = ".rscboundary.sock"
+
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  struct {
+		Path    string  `json:"path"`
+		Content *string `json:"content"`
+	} `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type daemon struct {
+	mu       sync.Mutex
+	roots    []ScanRoot
+	verbose  bool
+	state    *watchState
+	graph    *ImportGraph
+	listener net.Listener
+}
+
+func runDaemonCommand(args []string) error {
+	command := newGraphCommand("daemon")
+	socket := command.flags.String("socket", defaultDaemonSocket, "unix socket to listen on")
+	enable := command.flags.String("enable", "", "comma-separated opt-in rules to enable ("+RuleClientInLoop+", "+RuleServerAction+", "+RuleMissingClientDirective+", "+RuleNonSerializableProp+", "+RuleBrowserAPI+", "+RuleImportCasing+")")
+	command.flags.Parse(args)
+
+	config := DefaultConfig()
+	config.Flow = *command.flow
+	config.Enable = splitList(*enable)
+	roots, _, err := loadScanRoots(*command.path, *command.confPath, isFlagSet(command.flags, "path"), config)
+	if err != nil {
+		return err
+	}
+
+	if conn, err := net.Dial("unix", *socket); err == nil {
+		conn.Close()
+		return fmt.Errorf("a daemon is already listening on %s", *socket)
+	}
+	os.Remove(*socket)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	for _, root := range roots {
+		if err := watchTree(watcher, root.Path, root.Config); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	d := &daemon{
+		roots:   roots,
+		verbose: *command.verbose,
+		state:   &watchState{roots: roots, verbose: *command.verbose, files: make(map[string]*ScanResult)},
+	}
+	scanned := d.state.rescan(nil)
+
+	listener, err := net.Listen("unix", *socket)
+	if err != nil {
+		return err
+	}
+	d.listener = listener
+	defer os.Remove(*socket)
+	fmt.Fprintf(os.Stderr, "Listening on %s (%d files scanned in %s)\n", *socket, scanned, time.Since(start).Round(time.Millisecond))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+
+	go func() {
+		err := watchEvents(watcher, roots, func(changed map[string]bool, structural bool) error {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			rescanned := d.state.rescan(d.state.dependents(changed, structural))
+			d.graph = nil
+			if d.verbose {
+				fmt.Fprintf(os.Stderr, "--- %s: %d changed, %d files rescanned\n", time.Now().Format("15:04:05"), len(changed), rescanned)
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: watch: %v\n", err)
+		}
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		go d.serve(conn)
+	}
+}
+
+func (d *daemon) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		data, err := readMessage(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && d.verbose {
+				fmt.Fprintf(os.Stderr, "Warning: daemon: %v\n", err)
+			}
+			return
+		}
+
+		resp := rpcResponse{JSONRPC: "2.0"}
+		var req rpcRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			resp.Error = &rpcError{Code: rpcParseError, Message: err.Error()}
+		} else {
+			resp.ID = req.ID
+			resp.Result, resp.Error = d.handle(req)
+		}
+
+		if req.ID != nil || resp.Error != nil {
+			if err := writeMessage(conn, resp); err != nil {
+				return
+			}
+		}
+		if req.Method == "shutdown" {
+			d.listener.Close()
+			return
+		}
+	}
+}
+
+func (d *daemon) handle(req rpcRequest) (interface{}, *rpcError) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch req.Method {
+	case "findings":
+		if req.Params.Path == "" {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "findings requires params.path"}
+		}
+		result, err := d.findings(req.Params.Path, req.Params.Content)
+		if err != nil {
+			return nil, &rpcError{Code: rpcInternalError, Message: err.Error()}
+		}
+		return result, nil
+	case "graph":
+		path := req.Params.Path
+		if path == "" {
+			path = d.roots[0].Path
+		}
+		return filterBoundaryGraph(buildBoundaryGraph(d.importGraph(), d.roots[0].Config, d.verbose), path), nil
+	case "isClient":
+		graph := d.importGraph()
+		node := graph.load(req.Params.Path, rootConfig(d.roots, req.Params.Path), d.verbose)
+		return clientStatus{Path: node.Path, IsClient: graph.boundaryChain(node.Path) != nil, Directive: node.IsClient}, nil
+	case "boundaryChain":
+		graph := d.importGraph()
+		node := graph.load(req.Params.Path, rootConfig(d.roots, req.Params.Path), d.verbose)
+		chain := graph.boundaryChain(node.Path)
+		if chain == nil {
+			chain = []string{}
+		}
+		return boundaryChainResult{Path: node.Path, Chain: chain}, nil
+	case "reload":
+		for _, root := range d.roots {
+			root.Config.projects.forget()
+			root.Config.subpathImports.forget()
+			root.Config.importMaps.forget()
+			root.Config.listings.forget()
+			root.Config.cache.forgetHashes()
+		}
+		d.state.files = make(map[string]*ScanResult)
+		d.graph = nil
+		return map[string]int{"files": d.state.rescan(nil)}, nil
+	case "shutdown":
+		return map[string]bool{"ok": true}, nil
+	case "":
+		return nil, &rpcError{Code: rpcInvalidRequest, Message: "missing method"}
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("unknown method: %s", req.Method)}
+	}
+}
+
+func (d *daemon) importGraph() *ImportGraph {
+	if d.graph == nil {
+		graph, err := buildImportGraph(d.roots, d.verbose)
+		if err != nil {
+			graph = &ImportGraph{Nodes: make(map[string]*GraphNode)}
+		}
+		d.graph = graph
+	}
+	return d.graph
+}
+
+func (d *daemon) findings(path string, content *string) (*ScanResult, error) {
+	result := &ScanResult{}
+	abs := absPath(path)
+
+	switch file, ok := d.state.files[abs]; {
+	case content != nil:
+		if err := scanContent(path, []byte(*content), rootConfig(d.roots, path), d.verbose, result); err != nil {
+			return nil, err
+		}
+	case ok:
+		result.Merge(file)
+	case isDir(path):
+		var paths []string
+		for file := range d.state.files {
+			if isWithin(file, abs) {
+				paths = append(paths, file)
+			}
+		}
+		sort.Strings(paths)
+		for _, file := range paths {
+			result.Merge(d.state.files[file])
+		}
+	default:
+		if err := scanFile(path, rootConfig(d.roots, path), d.verbose, result); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Resolved = nil
+	if result.Findings == nil {
+		result.Findings = []Finding{}
+	}
+	if result.Unresolved == nil {
+		result.Unresolved = []UnresolvedImport{}
+	}
+	sortFindings(result.Findings)
+	annotateRoutes(result, d.roots)
+	return result, nil
+}
+
+func filterBoundaryGraph(graph *BoundaryGraph, dir string) *BoundaryGraph {
+	abs := absPath(dir)
+	filtered := &BoundaryGraph{Nodes: []BoundaryNode{}, Edges: []BoundaryEdge{}}
+	keep := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		if isWithin(absPath(edge.From), abs) {
+			filtered.Edges = append(filtered.Edges, edge)
+			keep[edge.From] = true
+			keep[edge.To] = true
+		}
+	}
+	for _, node := range graph.Nodes {
+		if keep[node.File] {
+			filtered.Nodes = append(filtered.Nodes, node)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestDaemonServe(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"Button.tsx": "'use client'\nexport function Button() { return null }\n",
+		"page.tsx":   "import { Button } from './Button'\nexport default function Page() { return <Button /> }\n",
+	})
+	page := filepath.Join(root, "page.tsx")
+	roots := []ScanRoot{{Path: root, Config: DefaultConfig()}}
+	d := &daemon{roots: roots, state: &watchState{roots: roots, files: make(map[string]*ScanResult)}}
+	d.state.rescan(nil)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go d.serve(server)
+	reader := bufio.NewReader(client)
+
+	call := func(method string, params map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		if _, err := client.Write([]byte(frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params}))); err != nil {
+			t.Fatal(err)
+		}
+		data, err := readMessage(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	findings := func(resp map[string]interface{}) int {
+		t.Helper()
+		result, ok := resp["result"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("response without a result: %v", resp)
+		}
+		return len(result["findings"].([]interface{}))
+	}
+
+	if n := findings(call("findings", map[string]interface{}{"path": page})); n != 1 {
+		t.Errorf("findings for page.tsx = %d, want 1", n)
+	}
+	if n := findings(call("findings", map[string]interface{}{"path": root})); n != 1 {
+		t.Errorf("findings for the root = %d, want 1", n)
+	}
+	unsaved := "import { Button } from './Button'\nexport default function Page() { return <><Button /><Button /></> }\n"
+	if n := findings(call("findings", map[string]interface{}{"path": page, "content": unsaved})); n != 2 {
+		t.Errorf("findings for unsaved content = %d, want 2", n)
+	}
+
+	status := call("isClient", map[string]interface{}{"path": filepath.Join(root, "Button.tsx")})["result"].(map[string]interface{})
+	if status["isClient"] != true || status["directive"] != true {
+		t.Errorf("isClient(Button.tsx) = %v, want a client file with a directive", status)
+	}
+
+	writeFiles(t, root, map[string]string{"Button.tsx": "export function Button() { return null }\n"})
+	if files := call("reload", nil)["result"].(map[string]interface{})["files"]; files != float64(2) {
+		t.Errorf("reload rescanned %v files, want 2", files)
+	}
+	if n := findings(call("findings", map[string]interface{}{"path": page})); n != 0 {
+		t.Errorf("findings after removing the directive = %d, want 0", n)
+	}
+
+	errors := []struct {
+		method string
+		params map[string]interface{}
+		code   int
+	}{
+		{method: "findings", code: rpcInvalidParams},
+		{method: "nope", code: rpcMethodNotFound},
+		{method: "", code: rpcInvalidRequest},
+	}
+	for _, tt := range errors {
+		resp := call(tt.method, tt.params)
+		rpcErr, ok := resp["error"].(map[string]interface{})
+		if !ok || rpcErr["code"] != float64(tt.code) {
+			t.Errorf("%q returned %v, want error code %d", tt.method, resp, tt.code)
+		}
+	}
+
+	if _, err := client.Write([]byte("Content-Length: 3\r\n\r\n{x}")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := readMessage(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp rpcResponse
+	if err := json.Unmarshal(data, &resp); err != nil || resp.Error == nil || resp.Error.Code != rpcParseError {
+		t.Errorf("malformed request returned %s, want a parse error", data)
+	}
+}
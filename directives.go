@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+type directiveMemo struct {
+	mu      sync.Mutex
+	entries map[string]DirectiveEntry
+}
+
+func newDirectiveMemo() *directiveMemo {
+	return &directiveMemo{entries: make(map[string]DirectiveEntry)}
+}
+
+func directiveKey(path string, config *Config) string {
+	return path + "\x00" + strings.Join(config.Directives, "\x00")
+}
+
+func (m *directiveMemo) lookup(key string, info os.FileInfo) (bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || entry.ModTime != info.ModTime().UnixNano() || entry.Size != info.Size() {
+		return false, false
+	}
+	return entry.IsClient, true
+}
+
+func (m *directiveMemo) store(key string, info os.FileInfo, isClient bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = DirectiveEntry{
+		ModTime:  info.ModTime().UnixNano(),
+		Size:     info.Size(),
+		IsClient: isClient,
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	DoctorOK   = "ok"
+	DoctorWarn = "warn"
+	DoctorFail = "fail"
+)
+
+type DoctorCheck struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+var rscFrameworks = []struct{ pkg, name string }{
+	{"next", "Next.js"},
+	{"waku", "Waku"},
+	{"@redwoodjs/core", "RedwoodJS"},
+	{"react-server-dom-webpack", "React Server DOM (webpack)"},
+	{"react-server-dom-parcel", "React Server DOM (Parcel)"},
+	{"react-server-dom-turbopack", "React Server DOM (Turbopack)"},
+}
+
+func runDoctorCommand(args []string) error {
+	command := newGraphCommand("doctor")
+	command.flags.Parse(args)
+
+	config := DefaultConfig()
+	config.Flow = *command.flow
+	checks, roots := doctorConfigChecks(*command.path, *command.confPath, isFlagSet(command.flags, "path"), config)
+	for _, root := range roots {
+		checks = append(checks, doctorRootChecks(root, *command.verbose)...)
+	}
+
+	if err := command.write(os.Stdout, checks, func(w io.Writer) error {
+		return writeDoctorText(w, checks)
+	}); err != nil {
+		return err
+	}
+
+	failures := 0
+	for _, check := range checks {
+		if check.Status == DoctorFail {
+			failures++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d checks failed", failures)
+	}
+	return nil
+}
+
+func doctorConfigChecks(path, configPath string, explicitPath bool, config *Config) ([]DoctorCheck, []ScanRoot) {
+	name := configPath
+	if name == "" {
+		name = configFileName
+	}
+
+	roots, fileConfig, err := loadScanRoots(path, configPath, explicitPath, config)
+	if err != nil {
+		return []DoctorCheck{{
+			Check:  "config file",
+			Status: DoctorFail,
+			Detail: fmt.Sprintf("%s: %v", name, err),
+			Fix:    fmt.Sprintf("fix the syntax of %s or pass another file with -config", name),
+		}}, nil
+	}
+
+	var checks []DoctorCheck
+	if fileConfig == nil {
+		checks = append(checks, DoctorCheck{Check: "config file", Status: DoctorOK, Detail: fmt.Sprintf("no %s above %s, scanning it with defaults", strings.Join(configFileNames, " or "), path)})
+	} else {
+		checks = append(checks, DoctorCheck{Check: "config file", Status: DoctorOK, Detail: fmt.Sprintf("%s (%d roots)", fileConfig.Path, len(fileConfig.Roots))})
+	}
+
+	var existing []ScanRoot
+	for _, root := range roots {
+		info, err := os.Stat(root.Path)
+		if err != nil || !info.IsDir() {
+			checks = append(checks, DoctorCheck{
+				Check:  "root",
+				Status: DoctorFail,
+				Detail: fmt.Sprintf("%s is not a directory", root.Path),
+				Fix:    "fix the root path in the config file or -path",
+			})
+			continue
+		}
+		existing = append(existing, root)
+	}
+	return checks, existing
+}
+
+func doctorRootChecks(root ScanRoot, verbose bool) []DoctorCheck {
+	var checks []DoctorCheck
+	checks = append(checks, doctorFilesCheck(root)...)
+	checks = append(checks, doctorProjectChecks(root)...)
+	checks = append(checks, doctorFrameworkCheck(root))
+	checks = append(checks, doctorImportsChecks(root, verbose)...)
+	return checks
+}
+
+func doctorFilesCheck(root ScanRoot) []DoctorCheck {
+	unfiltered := *root.Config
+	unfiltered.Ignore = nil
+
+	var files []string
+	walkSourceFiles(root.Path, &unfiltered, func(path string) {
+		if rel, err := filepath.Rel(root.Path, path); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+	})
+
+	if len(files) == 0 {
+		return []DoctorCheck{{
+			Check:  "source files",
+			Status: DoctorFail,
+			Detail: fmt.Sprintf("no %s files in %s", strings.Join(root.Config.SearchExtensions, "/"), root.Path),
+			Fix:    "point -path or the root at the source directory, or adjust the root's extensions",
+		}}
+	}
+
+	checks := []DoctorCheck{{Check: "source files", Status: DoctorOK, Detail: fmt.Sprintf("%d files in %s", len(files), root.Path)}}
+	for _, pattern := range root.Config.Ignore {
+		matched := 0
+		for _, file := range files {
+			if isIgnoredPath(pattern, file) {
+				matched++
+			}
+		}
+
+		switch {
+		case matched == 0:
+			checks = append(checks, DoctorCheck{
+				Check:  "ignore",
+				Status: DoctorWarn,
+				Detail: fmt.Sprintf("'%s' matches no files in %s", pattern, root.Path),
+				Fix:    "ignore globs are relative to the root; use ** to match any number of directories",
+			})
+		case matched == len(files):
+			checks = append(checks, DoctorCheck{
+				Check:  "ignore",
+				Status: DoctorFail,
+				Detail: fmt.Sprintf("'%s' excludes every file in %s", pattern, root.Path),
+				Fix:    "narrow the glob so it only matches the files to skip",
+			})
+		default:
+			checks = append(checks, DoctorCheck{Check: "ignore", Status: DoctorOK, Detail: fmt.Sprintf("'%s' excludes %d of %d files", pattern, matched, len(files))})
+		}
+	}
+	return checks
+}
+
+func isIgnoredPath(pattern, rel string) bool {
+	parts := strings.Split(rel, "/")
+	for i := range parts {
+		if matchGlob(pattern, strings.Join(parts[:i+1], "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func doctorProjectChecks(root ScanRoot) []DoctorCheck {
+	configPath := root.Config.Project
+	if configPath == "" {
+		configPath = findProjectConfig(filepath.Join(absPath(root.Path), "index.tsx"))
+	}
+	if configPath == "" {
+		return []DoctorCheck{{
+			Check:  "tsconfig",
+			Status: DoctorWarn,
+			Detail: fmt.Sprintf("no tsconfig.json or jsconfig.json above %s", root.Path),
+			Fix:    "path aliases won't resolve; add a tsconfig.json/jsconfig.json or pass -project",
+		}}
+	}
+
+	project, err := parseProjectConfig(configPath)
+	if err != nil {
+		return []DoctorCheck{{
+			Check:  "tsconfig",
+			Status: DoctorFail,
+			Detail: fmt.Sprintf("%s: %v", displayPath(configPath), err),
+			Fix:    "fix the file or its extends chain",
+		}}
+	}
+
+	checks := []DoctorCheck{{Check: "tsconfig", Status: DoctorOK, Detail: fmt.Sprintf("%s (%d aliases)", displayPath(configPath), len(project.Aliases))}}
+	for _, alias := range project.Aliases {
+		paths, _ := expandPath(alias.Target, root.Config)
+		if len(paths) > 0 {
+			continue
+		}
+		if info, err := os.Stat(alias.Target); err == nil && info.IsDir() {
+			continue
+		}
+		checks = append(checks, DoctorCheck{
+			Check:  "alias",
+			Status: DoctorWarn,
+			Detail: fmt.Sprintf("'%s' points to missing %s", alias.Pattern, displayPath(alias.Target)),
+			Fix:    fmt.Sprintf("fix compilerOptions.paths or baseUrl in %s", displayPath(configPath)),
+		})
+	}
+	return checks
+}
+
+func doctorFrameworkCheck(root ScanRoot) DoctorCheck {
+	manifest := findUp(absPath(root.Path), "package.json")
+	if manifest == "" {
+		return DoctorCheck{
+			Check:  "framework",
+			Status: DoctorWarn,
+			Detail: fmt.Sprintf("no package.json above %s", root.Path),
+			Fix:    "run the tool inside the application or package that uses React Server Components",
+		}
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	data, err := os.ReadFile(manifest)
+	if err == nil {
+		err = json.Unmarshal(data, &pkg)
+	}
+	if err != nil {
+		return DoctorCheck{Check: "framework", Status: DoctorFail, Detail: fmt.Sprintf("%s: %v", displayPath(manifest), err), Fix: "fix the package.json syntax"}
+	}
+
+	for _, framework := range rscFrameworks {
+		version, ok := pkg.Dependencies[framework.pkg]
+		if !ok {
+			version, ok = pkg.DevDependencies[framework.pkg]
+		}
+		if !ok {
+			continue
+		}
+
+		if framework.pkg == "next" {
+			dir := filepath.Dir(manifest)
+			if !isDir(filepath.Join(dir, "app")) && !isDir(filepath.Join(dir, "src", "app")) {
+				return DoctorCheck{
+					Check:  "framework",
+					Status: DoctorWarn,
+					Detail: fmt.Sprintf("Next.js %s without an app/ directory", version),
+					Fix:    "only the App Router renders server components; Pages Router files are all client code",
+				}
+			}
+		}
+		return DoctorCheck{Check: "framework", Status: DoctorOK, Detail: fmt.Sprintf("%s %s (%s)", framework.name, version, displayPath(manifest))}
+	}
+
+	return DoctorCheck{
+		Check:  "framework",
+		Status: DoctorWarn,
+		Detail: fmt.Sprintf("no React Server Components framework in %s", displayPath(manifest)),
+		Fix:    "'use client' boundaries only matter with an RSC framework such as Next.js App Router",
+	}
+}
+
+func doctorImportsChecks(root ScanRoot, verbose bool) []DoctorCheck {
+	result := &ScanResult{}
+	if err := scanPath(root.Path, root.Config, verbose, result); err != nil {
+		return []DoctorCheck{{Check: "imports", Status: DoctorFail, Detail: err.Error()}}
+	}
+
+	var checks []DoctorCheck
+	if len(result.Unresolved) == 0 {
+		checks = append(checks, DoctorCheck{Check: "imports", Status: DoctorOK, Detail: fmt.Sprintf("all local imports in %s resolve", root.Path)})
+	} else {
+		var examples []string
+		for i, u := range result.Unresolved {
+			if i == 3 {
+				break
+			}
+			examples = append(examples, fmt.Sprintf("%s:%d '%s'", u.File, u.Line, u.Source))
+		}
+		checks = append(checks, DoctorCheck{
+			Check:  "imports",
+			Status: DoctorWarn,
+			Detail: fmt.Sprintf("%d local imports don't resolve, e.g. %s", len(result.Unresolved), strings.Join(examples, ", ")),
+			Fix:    "check tsconfig paths, -import-map or extensions; -explain-resolution shows every candidate tried",
+		})
+	}
+
+	clientFiles := 0
+	walkSourceFiles(root.Path, root.Config, func(path string) {
+		if isClientFile(path, root.Config) {
+			clientFiles++
+		}
+	})
+	if clientFiles == 0 {
+		checks = append(checks, DoctorCheck{
+			Check:  "directives",
+			Status: DoctorWarn,
+			Detail: fmt.Sprintf("no file in %s starts with %s", root.Path, strings.Join(root.Config.Directives, " or ")),
+			Fix:    "client components may live outside the root (see -follow-outside-root) or use other directives",
+		})
+	} else {
+		checks = append(checks, DoctorCheck{Check: "directives", Status: DoctorOK, Detail: fmt.Sprintf("%d client files in %s", clientFiles, root.Path)})
+	}
+	return checks
+}
+
+func writeDoctorText(w io.Writer, checks []DoctorCheck) error {
+	for _, check := range checks {
+		fmt.Fprintf(w, "%-4s  %s: %s\n", check.Status, check.Check, check.Detail)
+		if check.Fix != "" && check.Status != DoctorOK {
+			fmt.Fprintf(w, "      fix: %s\n", check.Fix)
+		}
+	}
+	return nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
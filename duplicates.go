@@ -0,0 +1,159 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+var (
+	exportedComponentRegex = regexp.MustCompile(`(?m)^\s*export\s+(?:default\s+)?(?:async\s+)?(?:function\s*\*?|const|let|var|class)\s+(\p{Lu}[` + identPartChars + `]*)`)
+	exportListRegex        = regexp.MustCompile(`(?m)^\s*export\s*\{([^}]*)\}\s*;?\s*$`)
+	sourceTokenRegex       = regexp.MustCompile(identPattern + `|\d+|\S`)
+	lineCommentRegex       = regexp.MustCompile(`(?m)//.*$`)
+	blockCommentRegex      = regexp.MustCompile(`(?s)/\*.*?\*/`)
+)
+
+type DuplicateComponent struct {
+	Component  string   `json:"component"`
+	Similarity float64  `json:"similarity"`
+	Files      []string `json:"files"`
+}
+
+type componentSource struct {
+	path    string
+	hash    [32]byte
+	shingle map[string]bool
+}
+
+func runDuplicatesCommand(args []string) error {
+	command := newGraphCommand("duplicates")
+	threshold := command.flags.Float64("threshold", 0.8, "minimum token similarity (0-1) to report")
+	_, graph, err := command.parse(args)
+	if err != nil {
+		return err
+	}
+
+	duplicates := findDuplicateComponents(graph.clientFiles(), *threshold)
+
+	return command.write(os.Stdout, duplicates, func(w io.Writer) error {
+		return writeDuplicatesText(w, duplicates)
+	})
+}
+
+func findDuplicateComponents(clientFiles []string, threshold float64) []DuplicateComponent {
+	byName := make(map[string][]componentSource)
+
+	for _, path := range clientFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		normalized := normalizeSource(string(content))
+		source := componentSource{
+			path:    displayPath(path),
+			hash:    sha256.Sum256([]byte(strings.Join(normalized, " "))),
+			shingle: tokenShingles(normalized),
+		}
+		for _, name := range exportedComponents(string(content)) {
+			byName[name] = append(byName[name], source)
+		}
+	}
+
+	var duplicates []DuplicateComponent
+	for name, sources := range byName {
+		for i := 0; i < len(sources); i++ {
+			for j := i + 1; j < len(sources); j++ {
+				similarity := 1.0
+				if sources[i].hash != sources[j].hash {
+					similarity = jaccard(sources[i].shingle, sources[j].shingle)
+				}
+				if similarity < threshold {
+					continue
+				}
+				duplicates = append(duplicates, DuplicateComponent{
+					Component:  name,
+					Similarity: similarity,
+					Files:      []string{sources[i].path, sources[j].path},
+				})
+			}
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Similarity != duplicates[j].Similarity {
+			return duplicates[i].Similarity > duplicates[j].Similarity
+		}
+		if duplicates[i].Component != duplicates[j].Component {
+			return duplicates[i].Component < duplicates[j].Component
+		}
+		return duplicates[i].Files[0] < duplicates[j].Files[0]
+	})
+
+	return duplicates
+}
+
+func exportedComponents(content string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] && name[0] >= 'A' && name[0] <= 'Z' {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, match := range exportedComponentRegex.FindAllStringSubmatch(content, -1) {
+		add(match[1])
+	}
+	for _, match := range exportListRegex.FindAllStringSubmatch(content, -1) {
+		for _, name := range parseNamedSpecifiers(match[1]) {
+			add(name)
+		}
+	}
+
+	return names
+}
+
+func normalizeSource(content string) []string {
+	content = blockCommentRegex.ReplaceAllString(content, "")
+	content = lineCommentRegex.ReplaceAllString(content, "")
+	return sourceTokenRegex.FindAllString(content, -1)
+}
+
+func tokenShingles(tokens []string) map[string]bool {
+	shingles := make(map[string]bool)
+	for i := 0; i+1 < len(tokens); i++ {
+		shingles[tokens[i]+" "+tokens[i+1]] = true
+	}
+	return shingles
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	intersection := 0
+	for shingle := range a {
+		if b[shingle] {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+func writeDuplicatesText(w io.Writer, duplicates []DuplicateComponent) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMPONENT\tSIMILARITY\tFILES")
+	for _, duplicate := range duplicates {
+		fmt.Fprintf(tw, "%s\t%.2f\t%s\n", duplicate.Component, duplicate.Similarity, strings.Join(duplicate.Files, " "))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+var conditionalOperatorRegex = regexp.MustCompile(`&&|\|\||\?\?|\?[^.?]`)
+
+type DynamicCandidate struct {
+	Component     string   `json:"component"`
+	ClientFile    string   `json:"clientFile"`
+	DeferredBytes int64    `json:"deferredBytes"`
+	Usages        []string `json:"usages"`
+}
+
+type jsxContainer struct {
+	start, end  int
+	conditional bool
+}
+
+func runDynamicCommand(args []string) error {
+	command := newGraphCommand("dynamic")
+	config, graph, err := command.parse(args)
+	if err != nil {
+		return err
+	}
+
+	candidates := findDynamicCandidates(graph, config, *command.verbose)
+
+	return command.write(os.Stdout, candidates, func(w io.Writer) error {
+		return writeDynamicText(w, candidates)
+	})
+}
+
+func findDynamicCandidates(graph *ImportGraph, config *Config, verbose bool) []DynamicCandidate {
+	type key struct{ component, clientFile, imported string }
+	usages := make(map[key][]string)
+	unconditional := make(map[key]bool)
+
+	var serverFiles []string
+	for path, node := range graph.Nodes {
+		if node.loaded && !node.IsClient {
+			serverFiles = append(serverFiles, path)
+		}
+	}
+	sort.Strings(serverFiles)
+
+	for _, path := range serverFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		text := string(content)
+		containers := findJSXContainers(text)
+
+		for _, edge := range graph.Nodes[path].Imports {
+			if !graph.load(edge.Target, config, verbose).IsClient {
+				continue
+			}
+			for i, spec := range edge.Specifiers {
+				k := key{spec, edge.Target, ""}
+				if i < len(edge.Names) {
+					k.imported = edge.Names[i]
+				}
+				tagRegex := regexp.MustCompile(jsxTagPattern(spec))
+				for _, loc := range tagRegex.FindAllStringIndex(text, -1) {
+					usages[k] = append(usages[k], fmt.Sprintf("%s:%d", displayPath(path), lineAt(text, loc[0])))
+					if !isConditionalAt(containers, loc[0]) {
+						unconditional[k] = true
+					}
+				}
+			}
+		}
+	}
+
+	var candidates []DynamicCandidate
+	for k, sites := range usages {
+		if unconditional[k] {
+			continue
+		}
+		candidate := DynamicCandidate{
+			Component:  k.component,
+			ClientFile: displayPath(k.clientFile),
+			Usages:     sites,
+		}
+		var imported []string
+		if k.imported != "" {
+			imported = []string{k.imported}
+		}
+		for _, node := range graph.importClosure(k.clientFile, imported, config, verbose) {
+			candidate.DeferredBytes += node.Size
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].DeferredBytes != candidates[j].DeferredBytes {
+			return candidates[i].DeferredBytes > candidates[j].DeferredBytes
+		}
+		return candidates[i].Component < candidates[j].Component
+	})
+
+	return candidates
+}
+
+func findJSXContainers(text string) []jsxContainer {
+	var containers []jsxContainer
+	var stack []int
+
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			stack = append(stack, i)
+		case '}':
+			if len(stack) == 0 {
+				continue
+			}
+			start := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if opensJSXContainer(text, start) {
+				body := text[start+1 : i]
+				if idx := strings.Index(body, "<"); idx >= 0 {
+					body = body[:idx]
+				}
+				containers = append(containers, jsxContainer{
+					start:       start,
+					end:         i,
+					conditional: conditionalOperatorRegex.MatchString(body),
+				})
+			}
+		}
+	}
+
+	return containers
+}
+
+func opensJSXContainer(text string, start int) bool {
+	prev := strings.TrimRight(text[:start], " \t\r\n")
+	if prev == "" {
+		return false
+	}
+	switch prev[len(prev)-1] {
+	case '>':
+		return !strings.HasSuffix(prev, "=>")
+	case '}':
+		return true
+	case '=':
+		return start > 0 && text[start-1] == '=' && len(prev) > 1 && isIdentByte(prev[len(prev)-2])
+	}
+	return false
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= utf8.RuneSelf
+}
+
+func isConditionalAt(containers []jsxContainer, offset int) bool {
+	for _, container := range containers {
+		if container.conditional && container.start < offset && offset < container.end {
+			return true
+		}
+	}
+	return false
+}
+
+func lineAt(text string, offset int) int {
+	return strings.Count(text[:offset], "\n") + 1
+}
+
+func writeDynamicText(w io.Writer, candidates []DynamicCandidate) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMPONENT\tCLIENT FILE\tDEFERRED BYTES\tUSAGES")
+	for _, candidate := range candidates {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", candidate.Component, candidate.ClientFile, candidate.DeferredBytes, strings.Join(candidate.Usages, ", "))
+	}
+	return tw.Flush()
+}
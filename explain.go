@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type Explanation struct {
+	Finding    Finding            `json:"finding"`
+	Import     *ExplainedImport   `json:"import,omitempty"`
+	Resolution []string           `json:"resolution,omitempty"`
+	Directive  *DirectiveLocation `json:"directive,omitempty"`
+	Match      string             `json:"match,omitempty"`
+}
+
+type ExplainedImport struct {
+	Line      int    `json:"line"`
+	Statement string `json:"statement"`
+	Name      string `json:"name,omitempty"`
+}
+
+type DirectiveLocation struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Directive string `json:"directive"`
+}
+
+func runExplainCommand(args []string) error {
+	command := newGraphCommand("explain")
+	enable := command.flags.String("enable", "", "comma-separated opt-in rules to enable ("+RuleClientInLoop+", "+RuleServerAction+", "+RuleMissingClientDirective+", "+RuleNonSerializableProp+", "+RuleBrowserAPI+", "+RuleImportCasing+")")
+	command.flags.Parse(args)
+	if command.flags.NArg() != 1 {
+		return fmt.Errorf("usage: go-rsc-boundary explain [flags] path/to/file.tsx:line[:column]")
+	}
+
+	filePath, line, column, err := parseLocation(command.flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	config := DefaultConfig()
+	config.Flow = *command.flow
+	config.Enable = splitList(*enable)
+	roots, _, err := loadScanRoots(*command.path, *command.confPath, isFlagSet(command.flags, "path"), config)
+	if err != nil {
+		return err
+	}
+
+	explanations, err := explainLocation(filePath, line, column, rootConfig(roots, filePath), *command.verbose)
+	if err != nil {
+		return err
+	}
+	if len(explanations) == 0 {
+		return fmt.Errorf("no findings at %s", command.flags.Arg(0))
+	}
+
+	return command.write(os.Stdout, explanations, func(w io.Writer) error {
+		return writeExplanationsText(w, explanations)
+	})
+}
+
+func parseLocation(location string) (string, int, int, error) {
+	parts := strings.Split(location, ":")
+	var numbers []int
+	for len(parts) > 1 && len(numbers) < 2 {
+		n, err := strconv.Atoi(parts[len(parts)-1])
+		if err != nil {
+			break
+		}
+		numbers = append([]int{n}, numbers...)
+		parts = parts[:len(parts)-1]
+	}
+	if len(numbers) == 0 {
+		return "", 0, 0, fmt.Errorf("invalid location '%s' (want file:line or file:line:column)", location)
+	}
+
+	filePath := strings.Join(parts, ":")
+	if len(numbers) == 1 {
+		return filePath, numbers[0], 0, nil
+	}
+	return filePath, numbers[0], numbers[1], nil
+}
+
+func explainLocation(filePath string, line, column int, base *Config, verbose bool) ([]Explanation, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var trace bytes.Buffer
+	config := *base
+	config.ExplainResolution = true
+	config.explainOutput = &trace
+
+	result := &ScanResult{}
+	if err := scanContent(filePath, content, &config, verbose, result); err != nil {
+		return nil, err
+	}
+
+	lines := splitLines(string(content))
+	if config.Flow {
+		lines = stripFlowTypes(lines)
+	}
+	imports := append(parseImports(lines), parseLazyImports(lines)...)
+
+	var explanations []Explanation
+	for _, f := range result.Findings {
+		if f.Line != line || (column > 0 && f.Column != column) {
+			continue
+		}
+
+		explanation := Explanation{Finding: f}
+		if f.ImportSource != "" {
+			explanation.Import = findExplainedImport(imports, lines, f)
+			explanation.Resolution = resolutionTrace(trace.String(), filePath, f.ImportSource)
+		}
+		if f.ResolvedClientFile != "" {
+			explanation.Directive = findDirectiveLocation(f.ResolvedClientFile, base)
+		}
+		explanation.Match = explainMatch(f, explanation.Import, isClientFile(filePath, base))
+		explanations = append(explanations, explanation)
+	}
+	return explanations, nil
+}
+
+func findExplainedImport(imports []ImportInfo, lines []string, f Finding) *ExplainedImport {
+	for _, imp := range imports {
+		if imp.Source != f.ImportSource {
+			continue
+		}
+
+		explained := &ExplainedImport{Line: imp.Line, Statement: importStatement(lines, imp)}
+		component, _, _ := strings.Cut(f.Component, ".")
+		for i, spec := range imp.Specifiers {
+			if spec == component && i < len(imp.Names) {
+				explained.Name = imp.Names[i]
+			}
+		}
+		if component != "" && component == imp.Namespace {
+			explained.Name = "*"
+		}
+		return explained
+	}
+	return nil
+}
+
+func importStatement(lines []string, imp ImportInfo) string {
+	var parts []string
+	for _, line := range lines[imp.Line-1:] {
+		parts = append(parts, strings.TrimSpace(line))
+		if strings.Contains(line, "'"+imp.Source+"'") || strings.Contains(line, `"`+imp.Source+`"`) || len(parts) == 20 {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func resolutionTrace(trace, filePath, source string) []string {
+	header := fmt.Sprintf("explain: %s: import '%s'", filePath, source)
+
+	var steps []string
+	inBlock := false
+	for _, line := range strings.Split(trace, "\n") {
+		if line == header {
+			if steps != nil {
+				break
+			}
+			inBlock = true
+			steps = []string{}
+			continue
+		}
+		detail, ok := strings.CutPrefix(line, "explain:   ")
+		if !ok {
+			if inBlock {
+				break
+			}
+			continue
+		}
+		if inBlock {
+			steps = append(steps, detail)
+		}
+	}
+	return steps
+}
+
+func findDirectiveLocation(path string, config *Config) *DirectiveLocation {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	for _, directive := range directivePrologue(content) {
+		if containsString(config.Directives, directive.Text) {
+			return &DirectiveLocation{File: displayPath(path), Line: directive.Line, Directive: directive.Text}
+		}
+	}
+	return nil
+}
+
+func explainMatch(f Finding, imp *ExplainedImport, importerClient bool) string {
+	switch {
+	case f.Rule == RuleClientUsage && imp != nil:
+		binding := fmt.Sprintf("'%s'", f.Component)
+		if imp.Name != "" && imp.Name != f.Component {
+			binding += fmt.Sprintf(" (export '%s')", imp.Name)
+		}
+		importer := fmt.Sprintf("%s has no client directive", displayPath(f.File))
+		if importerClient {
+			importer = fmt.Sprintf("%s is itself a client file", displayPath(f.File))
+		}
+		if f.ResolvedClientFile == "" {
+			return fmt.Sprintf("<%s> at column %d is a JSX element whose tag is the binding %s imported from '%s' on line %d, a package listed in clientPackages, while %s", f.Component, f.Column, binding, f.ImportSource, imp.Line, importer)
+		}
+		if usage := referenceUsage(f); usage != "" {
+			return fmt.Sprintf("%s at column %d is %s; the binding %s imported from '%s' on line %d resolves to a client file while %s", f.Component, f.Column, usage, binding, f.ImportSource, imp.Line, importer)
+		}
+		return fmt.Sprintf("<%s> at column %d is a JSX element whose tag is the binding %s imported from '%s' on line %d, and that import resolves to a client file while %s", f.Component, f.Column, binding, f.ImportSource, imp.Line, importer)
+	case f.Message != "":
+		return f.Message
+	default:
+		return ""
+	}
+}
+
+func referenceUsage(f Finding) string {
+	if f.Column <= 0 || f.Column > len(f.Content) || strings.HasPrefix(f.Content[f.Column-1:], "<") {
+		return ""
+	}
+	before := strings.TrimSpace(f.Content[:f.Column-1])
+	switch {
+	case strings.HasSuffix(before, "createElement("):
+		return "passed to createElement"
+	case strings.HasSuffix(before, "{"):
+		return "passed as a prop value"
+	default:
+		return "an element of an array"
+	}
+}
+
+func writeExplanationsText(w io.Writer, explanations []Explanation) error {
+	for i, e := range explanations {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		f := e.Finding
+		position := fmt.Sprintf("%s:%d", displayPath(f.File), f.Line)
+		if f.Column > 0 {
+			position += fmt.Sprintf(":%d", f.Column)
+		}
+		fmt.Fprintf(w, "%s: %s (%s)\n", position, f.Rule, f.Severity)
+		fmt.Fprintf(w, "  code:       %s\n", strings.TrimSpace(f.Content))
+		if e.Import != nil {
+			fmt.Fprintf(w, "  import:     line %d: %s\n", e.Import.Line, e.Import.Statement)
+		}
+		for j, step := range e.Resolution {
+			label := ""
+			if j == 0 {
+				label = "resolution:"
+			}
+			fmt.Fprintf(w, "  %-11s %s\n", label, step)
+		}
+		if f.ResolvedClientFile != "" {
+			fmt.Fprintf(w, "  resolved:   %s\n", displayPath(f.ResolvedClientFile))
+		}
+		if e.Directive != nil {
+			fmt.Fprintf(w, "  directive:  %s:%d: %s\n", e.Directive.File, e.Directive.Line, e.Directive.Directive)
+		}
+		if e.Match != "" {
+			fmt.Fprintf(w, "  match:      %s\n", e.Match)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExplainMatchImporterDirective(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"B.tsx":    "'use client'\nexport function B() { return null }\n",
+		"C.tsx":    "'use client'\nimport { B } from './B'\nexport function C() { return <B /> }\n",
+		"page.tsx": "import { B } from './B'\nexport default function P() { return <B /> }\n",
+	})
+
+	tests := []struct {
+		file string
+		line int
+		want string
+		not  string
+	}{
+		{"C.tsx", 3, "is itself a client file", "has no client directive"},
+		{"page.tsx", 2, "has no client directive", "is itself a client file"},
+	}
+	for _, tt := range tests {
+		explanations, err := explainLocation(filepath.Join(root, tt.file), tt.line, 0, DefaultConfig(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(explanations) != 1 {
+			t.Fatalf("explainLocation(%s:%d) returned %d explanations, want 1", tt.file, tt.line, len(explanations))
+		}
+		match := explanations[0].Match
+		if !strings.Contains(match, tt.want) || strings.Contains(match, tt.not) {
+			t.Errorf("explanation for %s = %q, want it to say %q", tt.file, match, tt.want)
+		}
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		location string
+		file     string
+		line     int
+		column   int
+		err      bool
+	}{
+		{location: "app/page.tsx:12", file: "app/page.tsx", line: 12},
+		{location: "app/page.tsx:12:7", file: "app/page.tsx", line: 12, column: 7},
+		{location: `C:\src\page.tsx:3:1`, file: `C:\src\page.tsx`, line: 3, column: 1},
+		{location: "app/page.tsx", err: true},
+	}
+	for _, tt := range tests {
+		file, line, column, err := parseLocation(tt.location)
+		if (err != nil) != tt.err || file != tt.file || line != tt.line || column != tt.column {
+			t.Errorf("parseLocation(%q) = %q, %d, %d, %v", tt.location, file, line, column, err)
+		}
+	}
+}
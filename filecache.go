@@ -0,0 +1,235 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	fileCacheFile    = "files.json"
+	fileCacheVersion = "9"
+)
+
+type FileEntry struct {
+	Name       string                     `json:"name"`
+	Hash       string                     `json:"hash"`
+	Options    string                     `json:"options"`
+	Deps       map[string]DependencyStamp `json:"deps"`
+	Findings   []Finding                  `json:"findings,omitempty"`
+	Unresolved []UnresolvedImport         `json:"unresolved,omitempty"`
+	Suppressed map[string]int             `json:"suppressed,omitempty"`
+	Resolved   []string                   `json:"resolved,omitempty"`
+	LastUsed   int64                      `json:"lastUsed"`
+}
+
+type DependencyStamp struct {
+	ModTime int64  `json:"modTime"`
+	Size    int64  `json:"size"`
+	Hash    string `json:"hash,omitempty"`
+	Missing bool   `json:"missing,omitempty"`
+}
+
+func (c *Config) dependsOn(path string) {
+	if c.deps != nil && path != "" {
+		c.deps[path] = true
+	}
+}
+
+// dependsOnProbes records the candidates findUp checks from dir upwards
+// before reaching found (or the filesystem root when nothing was found), so
+// creating one of them later invalidates results that were cached without it.
+func (c *Config) dependsOnProbes(dir, found string, names ...string) {
+	if c.deps == nil {
+		return
+	}
+	for {
+		for _, name := range names {
+			candidate := filepath.Join(dir, name)
+			if candidate == found {
+				return
+			}
+			c.deps[candidate] = true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+}
+
+func (c *Cache) stampOf(path string) DependencyStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return DependencyStamp{Missing: true}
+	}
+	stamp := DependencyStamp{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+	if info.Mode().IsRegular() {
+		stamp.Hash = c.contentHash(path, info, 0)
+	}
+	return stamp
+}
+
+func (c *Cache) forgetHashes() {
+	if c == nil {
+		return
+	}
+	c.hashMu.Lock()
+	c.hashes = nil
+	c.hashMu.Unlock()
+}
+
+func (c *Cache) contentHash(path string, info os.FileInfo, limit int64) string {
+	key := fmt.Sprintf("%s\x00%d\x00%d\x00%d", path, info.ModTime().UnixNano(), info.Size(), limit)
+	c.hashMu.Lock()
+	hash, ok := c.hashes[key]
+	c.hashMu.Unlock()
+	if ok {
+		return hash
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if limit > 0 {
+		r = io.LimitReader(file, limit)
+	}
+	sum := sha256.New()
+	if _, err := io.Copy(sum, r); err != nil {
+		return ""
+	}
+	hash = hex.EncodeToString(sum.Sum(nil))
+
+	c.hashMu.Lock()
+	if c.hashes == nil {
+		c.hashes = make(map[string]string)
+	}
+	c.hashes[key] = hash
+	c.hashMu.Unlock()
+	return hash
+}
+
+func (c *Cache) optionsKey(config *Config) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.options[config]; ok {
+		return key
+	}
+
+	options := *config
+	options.ExplainResolution = false
+	options.TraceAliases = false
+	options.Jobs = 0
+	data, _ := json.Marshal(options)
+	sum := sha256.Sum256(append([]byte(fileCacheVersion+"\n"), data...))
+	key := hex.EncodeToString(sum[:])
+
+	if c.options == nil {
+		c.options = make(map[*Config]string)
+	}
+	c.options[config] = key
+	return key
+}
+
+func (c *Cache) lookupFile(path, name, hash, options string) (*ScanResult, bool) {
+	c.mu.Lock()
+	entry, ok := c.Files[path]
+	c.mu.Unlock()
+
+	fresh := ok && entry.Name == name && entry.Hash == hash && entry.Options == options
+	for dep, stamp := range entry.Deps {
+		if !fresh {
+			break
+		}
+		fresh = c.stampOf(dep) == stamp
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !fresh {
+		c.Stats.FileMisses++
+		return nil, false
+	}
+	c.Stats.FileHits++
+	entry.LastUsed = time.Now().Unix()
+	c.Files[path] = entry
+
+	return &ScanResult{
+		Findings:   entry.Findings,
+		Unresolved: entry.Unresolved,
+		Suppressed: entry.Suppressed,
+		Resolved:   entry.Resolved,
+	}, true
+}
+
+func (c *Cache) storeFile(path string, entry FileEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Files == nil {
+		c.Files = make(map[string]FileEntry)
+	}
+	c.Files[path] = entry
+}
+
+func (c *Cache) scanContent(filePath string, content []byte, config *Config, verbose bool, result *ScanResult) error {
+	path := absPath(filePath)
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	options := c.optionsKey(config)
+
+	if cached, ok := c.lookupFile(path, filePath, hash, options); ok {
+		result.Merge(cached)
+		return nil
+	}
+
+	tracked := *config
+	tracked.deps = make(map[string]bool)
+	file := &ScanResult{}
+	if err := scanContent(filePath, content, &tracked, verbose, file); err != nil {
+		return err
+	}
+
+	deps := make(map[string]DependencyStamp, len(tracked.deps))
+	for dep := range tracked.deps {
+		if dep != path {
+			deps[dep] = c.stampOf(dep)
+		}
+	}
+	c.storeFile(path, FileEntry{
+		Name:       filePath,
+		Hash:       hash,
+		Options:    options,
+		Deps:       deps,
+		Findings:   file.Findings,
+		Unresolved: file.Unresolved,
+		Suppressed: file.Suppressed,
+		Resolved:   file.Resolved,
+		LastUsed:   time.Now().Unix(),
+	})
+
+	result.Merge(file)
+	return nil
+}
+
+func (c *Cache) pruneFiles(cutoff int64) int {
+	removed := 0
+	for path, entry := range c.Files {
+		if _, err := os.Stat(path); err == nil && entry.LastUsed >= cutoff {
+			continue
+		}
+		delete(c.Files, path)
+		removed++
+	}
+	return removed
+}
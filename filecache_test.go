@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDirectiveCacheDetectsSameSizeEdit(t *testing.T) {
+	root := t.TempDir()
+	cacheDir := t.TempDir()
+	writeFiles(t, root, map[string]string{"d.tsx": "'use xlient'\nexport function D() { return null }\n"})
+	path := filepath.Join(root, "d.tsx")
+	stamp := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+
+	config := cachedConfig(t, cacheDir)
+	if isClientFile(path, config) {
+		t.Fatal("isClientFile() = true before the edit")
+	}
+	saveCache(t, config)
+
+	writeFiles(t, root, map[string]string{"d.tsx": "'use client'\nexport function D() { return null }\n"})
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+	if !isClientFile(path, cachedConfig(t, cacheDir)) {
+		t.Error("isClientFile() served a stale entry after a same-size edit with the same mtime")
+	}
+}
+
+func TestFileCacheInvalidation(t *testing.T) {
+	root := t.TempDir()
+	cacheDir := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"d.tsx":    "'use dom'\nexport function D() { return null }\n",
+		"page.tsx": "import { D } from './d'\nexport default function P() { return <D /> }\n",
+	})
+	page := filepath.Join(root, "page.tsx")
+	scan := func(config *Config) int {
+		t.Helper()
+		result := &ScanResult{}
+		if err := scanFile(page, config, false, result); err != nil {
+			t.Fatal(err)
+		}
+		saveCache(t, config)
+		return len(result.Findings)
+	}
+
+	if n := scan(cachedConfig(t, cacheDir)); n != 0 {
+		t.Fatalf("first scan reported %d findings, want 0", n)
+	}
+	if n := scan(cachedConfig(t, cacheDir, "'use client'", "'use dom'")); n != 1 {
+		t.Errorf("scan after adding a directive reported %d findings, want 1", n)
+	}
+
+	config := cachedConfig(t, cacheDir, "'use client'", "'use dom'")
+	if n := scan(config); n != 1 || config.cache.Stats.FileHits != 1 {
+		t.Errorf("unchanged rescan reported %d findings with %d cache hits, want 1 finding from the cache", n, config.cache.Stats.FileHits)
+	}
+
+	dep := filepath.Join(root, "d.tsx")
+	info, err := os.Stat(dep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, root, map[string]string{"d.tsx": "'use xom'\nexport function D() { return null }\n"})
+	if err := os.Chtimes(dep, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if n := scan(cachedConfig(t, cacheDir, "'use client'", "'use dom'")); n != 0 {
+		t.Errorf("scan after a same-size dependency edit reported %d findings, want 0", n)
+	}
+}
+
+func TestFileCacheInvalidatedByNewConfigFile(t *testing.T) {
+	root := t.TempDir()
+	cacheDir := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"src/ui/B.tsx":     "'use client'\nexport function B() { return null }\n",
+		"src/app/page.tsx": "import { B } from '@/ui/B'\nexport default function P() { return <B /> }\n",
+	})
+	page := filepath.Join(root, "src", "app", "page.tsx")
+	scan := func() int {
+		t.Helper()
+		config := cachedConfig(t, cacheDir)
+		result := &ScanResult{}
+		if err := scanFile(page, config, false, result); err != nil {
+			t.Fatal(err)
+		}
+		saveCache(t, config)
+		return len(result.Findings)
+	}
+
+	if n := scan(); n != 0 {
+		t.Fatalf("scan without a tsconfig.json reported %d findings, want 0", n)
+	}
+	writeFiles(t, root, map[string]string{
+		"tsconfig.json": `{"compilerOptions": {"paths": {"@/*": ["./src/*"]}}}`,
+	})
+	if n := scan(); n != 1 {
+		t.Errorf("scan after adding tsconfig.json reported %d findings, want 1", n)
+	}
+}
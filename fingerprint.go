@@ -0,0 +1,21 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+func fingerprintFindings(r *ScanResult, start int) {
+	occurrences := make(map[string]int)
+	for i := start; i < len(r.Findings); i++ {
+		f := &r.Findings[i]
+		key := strings.Join([]string{f.Rule, f.Component, f.ImportSource, strings.Join(strings.Fields(f.Content), " ")}, "\x00")
+		n := occurrences[key]
+		occurrences[key]++
+
+		sum := sha256.Sum256([]byte(key + "\x00" + strconv.Itoa(n)))
+		f.Fingerprint = hex.EncodeToString(sum[:8])
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	FixInsertDirective  = "insert-directive"
+	FixExtractComponent = "extract-component"
+)
+
+var serverOnlyExportRegex = regexp.MustCompile(`^\s*export\s+(?:async\s+)?(?:const|let|var|function)\s+(metadata|generateMetadata|generateStaticParams|generateViewport|viewport|revalidate|dynamic|dynamicParams|fetchCache|runtime|preferredRegion|maxDuration)\b`)
+
+var asyncComponentRegex = regexp.MustCompile(`^\s*export\s+default\s+async\s+function\b`)
+
+type Fix struct {
+	File      string   `json:"file"`
+	Action    string   `json:"action"`
+	Directive string   `json:"directive,omitempty"`
+	Reasons   []string `json:"reasons"`
+	Blocker   string   `json:"blocker,omitempty"`
+	Diff      string   `json:"diff,omitempty"`
+	Applied   bool     `json:"applied"`
+}
+
+func runFixCommand(args []string) error {
+	command := newGraphCommand("fix")
+	dryRun := command.flags.Bool("dry-run", false, "print the changes as a unified diff instead of writing them")
+	command.flags.Parse(args)
+
+	config := DefaultConfig()
+	config.Flow = *command.flow
+	config.Enable = []string{RuleMissingClientDirective}
+	roots, _, err := loadScanRoots(*command.path, *command.confPath, isFlagSet(command.flags, "path"), config)
+	if err != nil {
+		return err
+	}
+
+	var result *ScanResult
+	if files := command.flags.Args(); len(files) > 0 {
+		result, err = scanFileList(files, roots, *command.verbose)
+	} else {
+		result, err = scanRoots(roots, *command.verbose)
+	}
+	if err != nil {
+		return err
+	}
+
+	fixes, err := planFixes(result.Findings, rootConfig(roots, *command.path))
+	if err != nil {
+		return err
+	}
+
+	for i := range fixes {
+		fix := &fixes[i]
+		if fix.Action != FixInsertDirective || *dryRun {
+			continue
+		}
+		if err := applyDirectiveFix(fix.File, fix.Directive); err != nil {
+			return err
+		}
+		fix.Applied = true
+	}
+
+	return command.write(os.Stdout, fixes, func(w io.Writer) error {
+		return writeFixesText(w, fixes, *dryRun)
+	})
+}
+
+func planFixes(findings []Finding, config *Config) ([]Fix, error) {
+	reasons := make(map[string][]string)
+	for _, f := range findings {
+		if f.Rule == RuleMissingClientDirective {
+			reasons[f.File] = append(reasons[f.File], fmt.Sprintf("line %d: %s", f.Line, f.Message))
+		}
+	}
+
+	files := make([]string, 0, len(reasons))
+	for file := range reasons {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var fixes []Fix
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		fix := Fix{File: file, Action: FixInsertDirective, Reasons: reasons[file]}
+		if blocker := directiveBlocker(file, string(content)); blocker != "" {
+			fix.Action = FixExtractComponent
+			fix.Blocker = blocker
+		} else {
+			fix.Directive = directiveLine(string(content), config)
+			fix.Diff = directiveDiff(file, string(content), fix.Directive)
+		}
+		fixes = append(fixes, fix)
+	}
+	return fixes, nil
+}
+
+func directiveBlocker(path, content string) string {
+	if isRouteEntry(path) {
+		return fmt.Sprintf("%s is a route entry and should stay a server component", filepath.Base(path))
+	}
+	for _, line := range splitLines(content) {
+		if m := serverOnlyExportRegex.FindStringSubmatch(line); m != nil {
+			return fmt.Sprintf("it exports '%s', which only works in server files", m[1])
+		}
+		if asyncComponentRegex.MatchString(line) {
+			return "its default export is an async component, which cannot run on the client"
+		}
+	}
+	for _, imp := range parseImports(splitLines(content)) {
+		if imp.Source == serverOnlyPackage || isServerBuiltin(imp.Source) {
+			return fmt.Sprintf("it imports '%s'", imp.Source)
+		}
+	}
+	return ""
+}
+
+func directiveLine(content string, config *Config) string {
+	directive := config.Directives[0]
+	for _, line := range splitLines(content) {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "import ") {
+			if strings.HasSuffix(line, ";") {
+				directive += ";"
+			}
+			break
+		}
+	}
+	return directive
+}
+
+func applyDirectiveFix(path, directive string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	newline := "\n"
+	if strings.Contains(string(content), "\r\n") {
+		newline = "\r\n"
+	}
+	hashbang, rest := splitHashbang(string(content))
+	fixed := hashbang + directive + newline + newline + rest
+	return os.WriteFile(path, []byte(fixed), info.Mode())
+}
+
+func splitHashbang(content string) (string, string) {
+	if !strings.HasPrefix(content, "#!") {
+		return "", content
+	}
+	end := strings.IndexByte(content, '\n')
+	if end < 0 {
+		return content + "\n", ""
+	}
+	return content[:end+1], content[end+1:]
+}
+
+func directiveDiff(path, content, directive string) string {
+	hashbang, rest := splitHashbang(content)
+	lines := splitLines(strings.TrimSuffix(rest, "\n"))
+	context := lines
+	if len(context) > 3 {
+		context = context[:3]
+	}
+	before := 0
+	if hashbang != "" {
+		before = 1
+	}
+
+	var b strings.Builder
+	name := filepath.ToSlash(path)
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", name, name)
+	fmt.Fprintf(&b, "@@ -1,%d +1,%d @@\n", before+len(context), before+len(context)+2)
+	if hashbang != "" {
+		fmt.Fprintf(&b, " %s\n", strings.TrimRight(hashbang, "\r\n"))
+	}
+	fmt.Fprintf(&b, "+%s\n+\n", directive)
+	for _, line := range context {
+		fmt.Fprintf(&b, " %s\n", line)
+	}
+	return b.String()
+}
+
+func writeFixesText(w io.Writer, fixes []Fix, dryRun bool) error {
+	if len(fixes) == 0 {
+		fmt.Fprintln(w, "Nothing to fix")
+		return nil
+	}
+
+	for _, fix := range fixes {
+		switch {
+		case fix.Action == FixInsertDirective && dryRun:
+			fmt.Fprint(w, fix.Diff)
+		case fix.Action == FixInsertDirective:
+			fmt.Fprintf(w, "%s: inserted %s\n", fix.File, fix.Directive)
+		default:
+			fmt.Fprintf(w, "%s: not adding a directive because %s; extract the interactive code into a child client component and render it from here:\n", fix.File, fix.Blocker)
+			for _, reason := range fix.Reasons {
+				fmt.Fprintf(w, "  %s\n", reason)
+			}
+		}
+	}
+	return nil
+}
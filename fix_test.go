@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyDirectiveFix(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "plain file",
+			content: "import { useState } from 'react'\n",
+			want:    "'use client'\n\nimport { useState } from 'react'\n",
+		},
+		{
+			name:    "semicolon style",
+			content: "import { useState } from 'react';\n",
+			want:    "'use client';\n\nimport { useState } from 'react';\n",
+		},
+		{
+			name:    "CRLF line endings",
+			content: "import { useState } from 'react'\r\n",
+			want:    "'use client'\r\n\r\nimport { useState } from 'react'\r\n",
+		},
+		{
+			name:    "hashbang stays first",
+			content: "#!/usr/bin/env node\nimport { useState } from 'react'\n",
+			want:    "#!/usr/bin/env node\n'use client'\n\nimport { useState } from 'react'\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Counter.tsx")
+			writeFiles(t, filepath.Dir(path), map[string]string{"Counter.tsx": tt.content})
+
+			if err := applyDirectiveFix(path, directiveLine(tt.content, DefaultConfig())); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("applyDirectiveFix() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirectiveDiff(t *testing.T) {
+	content := "#!/usr/bin/env node\nimport a from './a'\nimport b from './b'\nimport c from './c'\nimport d from './d'\n"
+	want := "--- a/src/Counter.tsx\n+++ b/src/Counter.tsx\n" +
+		"@@ -1,4 +1,6 @@\n" +
+		" #!/usr/bin/env node\n" +
+		"+'use client'\n+\n" +
+		" import a from './a'\n import b from './b'\n import c from './c'\n"
+
+	if got := directiveDiff("src/Counter.tsx", content, "'use client'"); got != want {
+		t.Errorf("directiveDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPlanFixes(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"Counter.tsx":  "import { useState } from 'react'\nexport function Counter() { const [n, setN] = useState(0); return n }\n",
+		"app/page.tsx": "import { useState } from 'react'\nexport default function Page() { useState(0); return null }\n",
+		"Meta.tsx":     "import { useState } from 'react'\nexport const metadata = {}\nexport function Meta() { useState(0); return null }\n",
+	})
+
+	config := DefaultConfig()
+	config.Enable = []string{RuleMissingClientDirective}
+	result := &ScanResult{}
+	if err := scanPath(root, config, false, result); err != nil {
+		t.Fatal(err)
+	}
+	fixes, err := planFixes(result.Findings, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := make(map[string]string)
+	for _, fix := range fixes {
+		rel, _ := filepath.Rel(root, fix.File)
+		actions[filepath.ToSlash(rel)] = fix.Action
+		if fix.Action == FixInsertDirective && fix.Diff == "" {
+			t.Errorf("%s: insert fix without a diff", rel)
+		}
+		if fix.Action == FixExtractComponent && fix.Blocker == "" {
+			t.Errorf("%s: extract fix without a blocker", rel)
+		}
+	}
+	want := map[string]string{
+		"Counter.tsx":  FixInsertDirective,
+		"app/page.tsx": FixExtractComponent,
+		"Meta.tsx":     FixExtractComponent,
+	}
+	for file, action := range want {
+		if actions[file] != action {
+			t.Errorf("%s: action %q, want %q", file, actions[file], action)
+		}
+	}
+	if len(actions) != len(want) {
+		t.Errorf("fixes for %v, want %v", actions, want)
+	}
+}
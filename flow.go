@@ -0,0 +1,286 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var flowDeclarationRegex = regexp.MustCompile(`^\s*(?:export\s+)?(?:opaque\s+type|type|interface|declare)\s+[{` + identStartChars + `]`)
+
+func stripFlowTypes(lines []string) []string {
+	stripped := make([]string, len(lines))
+	copy(stripped, lines)
+
+	depth := 0
+	inDeclaration := false
+	for i, line := range lines {
+		if !inDeclaration {
+			if !flowDeclarationRegex.MatchString(line) {
+				continue
+			}
+			inDeclaration = true
+			depth = 0
+		}
+
+		for _, c := range line {
+			switch c {
+			case '{', '(', '[', '<':
+				depth++
+			case '}', ')', ']', '>':
+				depth--
+			}
+		}
+		stripped[i] = ""
+
+		trimmed := strings.TrimSpace(line)
+		if depth <= 0 && !strings.HasSuffix(trimmed, "=") && !strings.HasSuffix(trimmed, "|") &&
+			!strings.HasSuffix(trimmed, "&") && !strings.HasSuffix(trimmed, ",") {
+			inDeclaration = false
+		}
+	}
+
+	return stripFlowAnnotations(stripped)
+}
+
+var flowNonMethodKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "with": true,
+	"return": true, "typeof": true, "await": true, "yield": true, "new": true, "in": true, "of": true,
+}
+
+type flowStripper struct {
+	tokens []token
+	blank  [][2]int
+}
+
+// stripFlowAnnotations blanks the inline annotations the declaration pass
+// leaves in place: parameter and return types of functions, arrow functions
+// and methods, and the types of const/let/var bindings. Annotations are
+// replaced with spaces so line and column numbers do not move. Type casts
+// such as (value: T) and class property annotations are left alone.
+func stripFlowAnnotations(lines []string) []string {
+	text := strings.Join(lines, "\n")
+	s := &flowStripper{tokens: tokenize(text)}
+	for i, t := range s.tokens {
+		switch {
+		case t.kind == tokenIdent && t.text == "function":
+			s.function(i)
+		case t.kind == tokenIdent && (t.text == "const" || t.text == "let" || t.text == "var"):
+			s.binding(i)
+		case t.text == "(":
+			s.arrowOrMethod(i)
+		}
+	}
+	if len(s.blank) == 0 {
+		return lines
+	}
+
+	buf := []byte(text)
+	for _, r := range s.blank {
+		for i := r[0]; i < r[1]; i++ {
+			if buf[i] != '\n' {
+				buf[i] = ' '
+			}
+		}
+	}
+	return strings.Split(string(buf), "\n")
+}
+
+func (s *flowStripper) text(i int) string {
+	return tokenText(s.tokens, i)
+}
+
+func (s *flowStripper) arrow(i int) bool {
+	return s.text(i) == "=" && s.text(i+1) == ">" && s.tokens[i+1].offset == s.tokens[i].offset+1
+}
+
+func (s *flowStripper) mark(from, to int) {
+	if from >= len(s.tokens) || from >= to {
+		return
+	}
+	end := len(s.tokens) - 1
+	if to < len(s.tokens) {
+		end = to
+	}
+	stop := s.tokens[end].offset
+	if to >= len(s.tokens) {
+		stop += len(s.tokens[end].text)
+	}
+	s.blank = append(s.blank, [2]int{s.tokens[from].offset, stop})
+}
+
+// closing returns the index of the token closing the bracket at open.
+func (s *flowStripper) closing(open int) int {
+	pair := map[string]string{"(": ")", "[": "]", "{": "}", "<": ">"}[s.text(open)]
+	depth := 0
+	for i := open; i < len(s.tokens); i++ {
+		switch {
+		case s.arrow(i):
+			i++
+		case s.text(i) == s.text(open):
+			depth++
+		case s.text(i) == pair:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s.tokens)
+}
+
+// typeEnd returns the index of the first token after the type starting at
+// from, stopping at a closing bracket of the enclosing list or where stop
+// reports true at bracket depth zero. An arrow at depth zero ends the type
+// unless it belongs to a function type, as in a parameter annotation.
+func (s *flowStripper) typeEnd(from int, arrows bool, stop func(i int) bool) int {
+	depth := 0
+	for i := from; i < len(s.tokens); i++ {
+		if depth == 0 && i > from && stop(i) {
+			return i
+		}
+		switch {
+		case s.arrow(i):
+			if depth == 0 && !arrows {
+				return i
+			}
+			i++
+		case strings.Contains("([{<", s.text(i)) && s.tokens[i].kind == tokenPunct:
+			depth++
+		case strings.Contains(")]}>", s.text(i)) && s.tokens[i].kind == tokenPunct:
+			depth--
+			if depth < 0 {
+				return i
+			}
+		}
+	}
+	return len(s.tokens)
+}
+
+// closesType reports whether the token at i can end a type, so a following
+// "{" starts a body rather than an object type.
+func (s *flowStripper) closesType(i int) bool {
+	t := s.tokens[i]
+	return t.kind == tokenIdent || t.kind == tokenString || t.kind == tokenNumber || strings.Contains(">)]}", t.text)
+}
+
+// returnType returns the end of the return type annotation after the
+// parameter list closing at close, and whether one is followed by a body or
+// an arrow.
+func (s *flowStripper) returnType(close int) (int, bool) {
+	if s.text(close+1) != ":" {
+		return close + 1, false
+	}
+	end := s.typeEnd(close+2, false, func(i int) bool {
+		return s.text(i) == "{" && s.closesType(i-1)
+	})
+	return end, end < len(s.tokens) && (s.text(end) == "{" || s.arrow(end))
+}
+
+func (s *flowStripper) params(open, close int) {
+	depth, inDefault := 0, false
+	for i := open + 1; i < close; i++ {
+		switch {
+		case s.arrow(i):
+			i++
+		case strings.Contains("([{", s.text(i)):
+			depth++
+		case strings.Contains(")]}", s.text(i)):
+			depth--
+		case depth > 0:
+		case s.text(i) == ",":
+			inDefault = false
+		case s.text(i) == "=":
+			inDefault = true
+		case s.text(i) == ":" && !inDefault:
+			from := i
+			if s.text(i-1) == "?" {
+				from = i - 1
+			}
+			end := s.typeEnd(i+1, true, func(j int) bool {
+				return s.text(j) == "," || (s.text(j) == "=" && !s.arrow(j))
+			})
+			if end > close {
+				end = close
+			}
+			s.mark(from, end)
+			i = end - 1
+		}
+	}
+}
+
+func (s *flowStripper) function(i int) {
+	j := i + 1
+	if s.text(j) == "*" {
+		j++
+	}
+	if j < len(s.tokens) && s.tokens[j].kind == tokenIdent {
+		j++
+	}
+	if s.text(j) == "<" {
+		close := s.closing(j)
+		s.mark(j, close+1)
+		j = close + 1
+	}
+	if s.text(j) != "(" {
+		return
+	}
+	close := s.closing(j)
+	s.params(j, close)
+	if end, ok := s.returnType(close); ok {
+		s.mark(close+1, end)
+	}
+}
+
+func (s *flowStripper) arrowOrMethod(open int) {
+	if s.text(open-1) == "function" || (open >= 2 && s.text(open-2) == "function") {
+		return
+	}
+	close := s.closing(open)
+	if close >= len(s.tokens) {
+		return
+	}
+	if s.arrow(close + 1) {
+		s.params(open, close)
+		return
+	}
+
+	end, ok := s.returnType(close)
+	isArrow := ok && s.arrow(end)
+	prev := open - 1
+	isMethod := prev >= 0 && s.tokens[prev].kind == tokenIdent && !flowNonMethodKeywords[s.text(prev)] && s.text(prev-1) != "." &&
+		(s.text(close+1) == "{" || (ok && s.text(end) == "{"))
+	if !isArrow && !isMethod {
+		return
+	}
+	s.params(open, close)
+	if ok {
+		s.mark(close+1, end)
+	}
+}
+
+func (s *flowStripper) binding(i int) {
+	j := i + 1
+	switch {
+	case s.text(j) == "{" || s.text(j) == "[":
+		j = s.closing(j) + 1
+	case j < len(s.tokens) && s.tokens[j].kind == tokenIdent:
+		j++
+	default:
+		return
+	}
+	if s.text(j) != ":" {
+		return
+	}
+	end := s.typeEnd(j+1, true, func(k int) bool {
+		switch s.text(k) {
+		case ";", ",":
+			return true
+		case "=":
+			return !s.arrow(k)
+		case "of", "in":
+			return s.closesType(k - 1)
+		}
+		return s.tokens[k].lineStart && s.text(k) != "|" && s.text(k) != "&"
+	})
+	s.mark(j, end)
+}
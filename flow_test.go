@@ -0,0 +1,97 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStripFlowTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "type declaration",
+			src:  "type Items = Array<Button>",
+			want: "",
+		},
+		{
+			name: "function parameters and return type",
+			src:  "function f(x: Props, items?: Array<Button> = []): React.Node {",
+			want: "function f(x       , items                 = [])             {",
+		},
+		{
+			name: "generic function",
+			src:  "function g<T: Button>(x: T) {}",
+			want: "function g           (x   ) {}",
+		},
+		{
+			name: "arrow function",
+			src:  "const h = (a: Array<Button>): Node => a",
+			want: "const h = (a               )       => a",
+		},
+		{
+			name: "arrow function parameter with a function type",
+			src:  "const k = (cb: (x: number) => void) => cb",
+			want: "const k = (cb                     ) => cb",
+		},
+		{
+			name: "method",
+			src:  "  render(props: Props): Element<Button> {",
+			want: "  render(props       )                  {",
+		},
+		{
+			name: "binding",
+			src:  "const items: Array<Button> = []",
+			want: "const items                = []",
+		},
+		{
+			name: "call expression is kept",
+			src:  "if (ready) { render(x) }",
+			want: "if (ready) { render(x) }",
+		},
+		{
+			name: "ternary is kept",
+			src:  "const z = cond ? (a) : <Button />",
+			want: "const z = cond ? (a) : <Button />",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(stripFlowTypes(splitLines(tt.src)), "\n")
+			if strings.TrimRight(got, " ") != strings.TrimRight(tt.want, " ") {
+				t.Errorf("stripFlowTypes(%q) =\n%q\nwant\n%q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanFlowAnnotations(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"Button.js": "'use client'\nexport function Button() { return null }\n",
+		"page.js": "// @flow\nimport { Button } from './Button'\n\n" +
+			"function f(x: Props, items: Array<Button>): React.Node {\n" +
+			"  const list: Array<Button> = []\n" +
+			"  return <Button />\n" +
+			"}\n",
+	})
+
+	config := DefaultConfig()
+	config.Flow = true
+	result := &ScanResult{}
+	if err := scanFile(filepath.Join(root, "page.js"), config, false, result); err != nil {
+		t.Fatal(err)
+	}
+	var lines []int
+	for _, f := range result.Findings {
+		lines = append(lines, f.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{6}) {
+		t.Errorf("findings on lines %v, want [6]", lines)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const frameworkNextJS = "nextjs"
+
+var frameworks = []string{frameworkNextJS}
+
+func frameworkExcluded(root, path string, config *Config) bool {
+	if config.Framework != frameworkNextJS {
+		return false
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	rel = strings.TrimPrefix(rel, "src/")
+
+	if rel == "pages" || strings.HasPrefix(rel, "pages/") {
+		return true
+	}
+	return strings.TrimSuffix(rel, filepath.Ext(rel)) == "middleware"
+}
+
+func annotateRoutes(result *ScanResult, roots []ScanRoot) {
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		root := scanRootFor(roots, f.File)
+		if root.Config.Framework != frameworkNextJS {
+			continue
+		}
+
+		rel, err := filepath.Rel(absPath(root.Path), absPath(f.File))
+		if err != nil || appDir(rel) == "" {
+			continue
+		}
+		f.Route = routeForDir(filepath.Dir(rel))
+	}
+}
+
+func scanRootFor(roots []ScanRoot, filePath string) ScanRoot {
+	for _, root := range roots {
+		if isWithin(absPath(filePath), absPath(root.Path)) {
+			return root
+		}
+	}
+	return roots[0]
+}
@@ -0,0 +1,48 @@
+package main
+
+import "strings"
+
+func isGeneratedFile(content []byte, config *Config) bool {
+	if int64(len(content)) > config.MaxReadBytes {
+		content = content[:config.MaxReadBytes]
+	}
+
+	inBlockComment := false
+	for _, line := range splitLines(string(content)) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		comment := inBlockComment
+		switch {
+		case inBlockComment:
+		case strings.HasPrefix(line, "//"), strings.HasPrefix(line, "#!"):
+			comment = true
+		case strings.HasPrefix(line, "/*"):
+			comment = true
+			inBlockComment = true
+		case isDirectiveLine(line):
+			continue
+		}
+		if !comment {
+			return false
+		}
+		if inBlockComment && strings.Contains(line, "*/") {
+			inBlockComment = false
+		}
+
+		for _, marker := range config.GeneratedMarkers {
+			if strings.Contains(line, marker) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isDirectiveLine(line string) bool {
+	line = strings.TrimSuffix(line, ";")
+	return len(line) >= 2 && (line[0] == '\'' || line[0] == '"') && line[len(line)-1] == line[0]
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const gitignoreFileName = ".gitignore"
+
+type gitignoreRule struct {
+	Base     string
+	Pattern  string
+	Negate   bool
+	DirOnly  bool
+	Anchored bool
+}
+
+type gitignore struct {
+	rules []gitignoreRule
+}
+
+func newGitignore(root string) *gitignore {
+	ignore := &gitignore{}
+
+	dir := absPath(root)
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	var ancestors []string
+	for current := dir; !isGitRoot(current); current = filepath.Dir(current) {
+		if filepath.Dir(current) == current {
+			ancestors = nil
+			break
+		}
+		ancestors = append(ancestors, filepath.Dir(current))
+	}
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		ignore.load(ancestors[i])
+	}
+	return ignore
+}
+
+func isGitRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+func (g *gitignore) load(dir string) {
+	file, err := os.Open(filepath.Join(dir, gitignoreFileName))
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseGitignoreLine(absPath(dir), scanner.Text()); ok {
+			g.rules = append(g.rules, rule)
+		}
+	}
+}
+
+func parseGitignoreLine(base, line string) (gitignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+
+	rule := gitignoreRule{Base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.Negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.DirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rule.Anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+
+	rule.Pattern = line
+	return rule, true
+}
+
+func (g *gitignore) ignored(path string, isDir bool) bool {
+	abs := absPath(path)
+	ignored := false
+	for _, rule := range g.rules {
+		if rule.DirOnly && !isDir {
+			continue
+		}
+
+		rel, err := filepath.Rel(rule.Base, abs)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		pattern := rule.Pattern
+		if !rule.Anchored {
+			pattern = "**/" + pattern
+		}
+		if matchGlob(pattern, rel) {
+			ignored = !rule.Negate
+		}
+	}
+	return ignored
+}
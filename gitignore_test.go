@@ -0,0 +1,75 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGitignoreLine(t *testing.T) {
+	base := filepath.FromSlash("/repo")
+	tests := []struct {
+		line string
+		want gitignoreRule
+		ok   bool
+	}{
+		{line: "", ok: false},
+		{line: "# comment", ok: false},
+		{line: "   ", ok: false},
+		{line: "/", ok: false},
+		{line: "dist", want: gitignoreRule{Base: base, Pattern: "dist"}, ok: true},
+		{line: "dist/", want: gitignoreRule{Base: base, Pattern: "dist", DirOnly: true}, ok: true},
+		{line: "/build", want: gitignoreRule{Base: base, Pattern: "build", Anchored: true}, ok: true},
+		{line: "src/gen", want: gitignoreRule{Base: base, Pattern: "src/gen", Anchored: true}, ok: true},
+		{line: "!keep.ts", want: gitignoreRule{Base: base, Pattern: "keep.ts", Negate: true}, ok: true},
+		{line: `\!bang`, want: gitignoreRule{Base: base, Pattern: "!bang"}, ok: true},
+		{line: `\#hash`, want: gitignoreRule{Base: base, Pattern: "#hash"}, ok: true},
+		{line: "*.log  \r", want: gitignoreRule{Base: base, Pattern: "*.log"}, ok: true},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseGitignoreLine(base, tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseGitignoreLine(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGitignoreIgnored(t *testing.T) {
+	root := t.TempDir()
+	ignore := &gitignore{}
+	for _, line := range []string{"dist/", "/build", "*.gen.ts", "!keep.gen.ts", "src/legacy"} {
+		if rule, ok := parseGitignoreLine(root, line); ok {
+			ignore.rules = append(ignore.rules, rule)
+		}
+	}
+	nested := filepath.Join(root, "packages", "ui")
+	if rule, ok := parseGitignoreLine(nested, "fixtures"); ok {
+		ignore.rules = append(ignore.rules, rule)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"dist", true, true},
+		{"apps/web/dist", true, true},
+		{"dist", false, false},
+		{"build", true, true},
+		{"apps/build", true, false},
+		{"src/api.gen.ts", false, true},
+		{"src/keep.gen.ts", false, false},
+		{"src/legacy", true, true},
+		{"app/src/legacy", true, false},
+		{"packages/ui/fixtures", true, true},
+		{"fixtures", true, false},
+		{"src/page.tsx", false, false},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(root, filepath.FromSlash(tt.path))
+		if got := ignore.ignored(path, tt.isDir); got != tt.want {
+			t.Errorf("ignored(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.tsx", "page.tsx", true},
+		{"*.tsx", "app/page.tsx", false},
+		{"app/*.tsx", "app/page.tsx", true},
+		{"**/*.stories.tsx", "Button.stories.tsx", true},
+		{"**/*.stories.tsx", "src/ui/Button.stories.tsx", true},
+		{"**/*.stories.tsx", "src/ui/Button.tsx", false},
+		{"src/**", "src/a/b/c.ts", true},
+		{"src/**", "lib/a.ts", false},
+		{"src/**/index.ts", "src/index.ts", true},
+		{"src/**/index.ts", "src/a/b/index.ts", true},
+		{"@scope/*", "@scope/ui", true},
+		{"@scope/*", "@scope/ui/button", false},
+		{"node_modules", "node_modules", true},
+		{"?.ts", "a.ts", true},
+		{"[ab].ts", "c.ts", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
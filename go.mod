@@ -1,3 +1,10 @@
 module github.com/conao3/go-rsc-boundary
 
 go 1.21
+
+require (
+	github.com/fsnotify/fsnotify v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.4.0 // indirect
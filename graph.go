@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type ImportGraph struct {
+	Nodes map[string]*GraphNode
+
+	packages  map[string]*packageSideEffects
+	diagnosed map[string]bool
+}
+
+type GraphNode struct {
+	Path      string
+	IsClient  bool
+	Size      int64
+	Lines     int
+	Imports   []GraphEdge
+	External  []GraphEdge
+	Importers []string
+
+	loaded    bool
+	reexports map[string][]string
+}
+
+type GraphEdge struct {
+	Target     string
+	Source     string
+	Specifiers []string
+	Names      []string
+}
+
+func buildImportGraph(roots []ScanRoot, verbose bool) (*ImportGraph, error) {
+	graph := &ImportGraph{Nodes: make(map[string]*GraphNode)}
+
+	for _, root := range roots {
+		config := root.Config
+		err := walkSourceFiles(root.Path, config, func(path string) {
+			graph.load(path, config, verbose)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return graph, nil
+}
+
+func (g *ImportGraph) node(path string) *GraphNode {
+	path = canonicalPath(path)
+	node, ok := g.Nodes[path]
+	if !ok {
+		node = &GraphNode{Path: path}
+		g.Nodes[path] = node
+	}
+	return node
+}
+
+func (g *ImportGraph) load(path string, config *Config, verbose bool) *GraphNode {
+	node := g.node(path)
+	if node.loaded {
+		return node
+	}
+	node.loaded = true
+	node.IsClient = isClientFile(node.Path, config)
+
+	if info, err := os.Stat(node.Path); err == nil && config.oversized(displayPath(node.Path), info.Size(), verbose) {
+		return node
+	}
+	content, err := os.ReadFile(node.Path)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read %s: %v\n", node.Path, err)
+		}
+		return node
+	}
+	node.Size = int64(len(content))
+	node.Lines = bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		node.Lines++
+	}
+
+	baseDir := filepath.Dir(node.Path)
+	project, _ := loadProjectConfig(node.Path, config)
+	importMap, _ := loadImportMap(baseDir, config)
+
+	lines := splitLines(string(content))
+	if config.Flow {
+		lines = stripFlowTypes(lines)
+	}
+	for _, imp := range parseImports(lines) {
+		resolution := resolveImportPath(baseDir, imp.Source, project, importMap, config)
+		if external, ok := externalSpecifier(baseDir, imp.Source, config); !resolution.Local && ok {
+			node.External = append(node.External, GraphEdge{
+				Source:     external,
+				Specifiers: imp.Specifiers,
+				Names:      imp.Names,
+			})
+		}
+		for _, resolvedPath := range resolution.Paths {
+			target := g.node(resolvedPath)
+			node.Imports = append(node.Imports, GraphEdge{
+				Target:     target.Path,
+				Source:     imp.Source,
+				Specifiers: imp.Specifiers,
+				Names:      imp.Names,
+			})
+			target.Importers = append(target.Importers, node.Path)
+		}
+	}
+
+	for _, match := range exportListRegex.FindAllStringSubmatch(string(content), -1) {
+		exported := parseNamedSpecifiers(match[1])
+		for i, local := range parseImportedNames(match[1]) {
+			if node.reexports == nil {
+				node.reexports = make(map[string][]string)
+			}
+			node.reexports[local] = append(node.reexports[local], exported[i])
+		}
+	}
+
+	return node
+}
+
+func (g *ImportGraph) closure(path string, config *Config, verbose bool) []*GraphNode {
+	return g.importClosure(path, nil, config, verbose)
+}
+
+func (g *ImportGraph) importClosure(path string, imported []string, config *Config, verbose bool) []*GraphNode {
+	type step struct {
+		path  string
+		names map[string]bool
+		depth int
+		hops  int
+	}
+
+	var names map[string]bool
+	for _, name := range imported {
+		if name == "*" {
+			names = nil
+			break
+		}
+		if names == nil {
+			names = make(map[string]bool)
+		}
+		names[name] = true
+	}
+
+	var nodes []*GraphNode
+	limits := config.Limits
+	start := canonicalPath(path)
+	used := make(map[string]map[string]bool)
+	parent := map[string]string{start: ""}
+	queue := []step{{path: start, names: names}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if previous, seen := used[current.path]; seen {
+			if previous == nil || coversNames(previous, current.names) {
+				continue
+			}
+			if current.names != nil {
+				for name := range previous {
+					current.names[name] = true
+				}
+			}
+			used[current.path] = current.names
+		} else {
+			used[current.path] = current.names
+			nodes = append(nodes, g.load(current.path, config, verbose))
+		}
+
+		node := g.Nodes[current.path]
+		if current.depth >= limits.MaxClosureDepth {
+			if len(node.Imports) > 0 {
+				g.diagnose("closure of %s truncated at %s (maxClosureDepth %d)", displayPath(start), displayPath(current.path), limits.MaxClosureDepth)
+			}
+			continue
+		}
+
+		for _, edge := range node.Imports {
+			hops := current.hops
+			if len(node.reexports) > 0 && current.names != nil {
+				hops++
+			}
+			needed := node.neededNames(edge, current.names)
+			if hops > limits.MaxReexportHops {
+				g.diagnose("closure of %s stops following re-exports at %s (maxReexportHops %d)", displayPath(start), displayPath(current.path), limits.MaxReexportHops)
+				needed = nil
+			}
+			if g.sideEffectFree(edge.Target) && (len(edge.Names) == 0 || len(needed) == 0) {
+				continue
+			}
+
+			if limits.Cycles == CyclesWarn {
+				if cycle := importCycle(parent, current.path, edge.Target); cycle != nil {
+					g.diagnose("import cycle: %s", formatCycle(cycle))
+				}
+			}
+			if _, ok := parent[edge.Target]; !ok {
+				parent[edge.Target] = current.path
+			}
+			queue = append(queue, step{path: edge.Target, names: needed, depth: current.depth + 1, hops: hops})
+		}
+	}
+
+	return nodes
+}
+
+func (n *GraphNode) neededNames(edge GraphEdge, used map[string]bool) map[string]bool {
+	needed := make(map[string]bool)
+	for i, name := range edge.Names {
+		if name == "*" {
+			return nil
+		}
+		if used != nil && i < len(edge.Specifiers) && len(n.reexports[edge.Specifiers[i]]) > 0 {
+			reexported := false
+			for _, exported := range n.reexports[edge.Specifiers[i]] {
+				reexported = reexported || used[exported]
+			}
+			if !reexported {
+				continue
+			}
+		}
+		needed[name] = true
+	}
+	return needed
+}
+
+func coversNames(have, names map[string]bool) bool {
+	if names == nil {
+		return false
+	}
+	for name := range names {
+		if !have[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *ImportGraph) clientFiles() []string {
+	var paths []string
+	for path, node := range g.Nodes {
+		if node.loaded && node.IsClient {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (g *ImportGraph) boundaries() []string {
+	var paths []string
+	for _, path := range g.clientFiles() {
+		node := g.Nodes[path]
+		isBoundary := len(node.Importers) == 0
+		for _, importer := range node.Importers {
+			if !g.Nodes[importer].IsClient {
+				isBoundary = true
+				break
+			}
+		}
+		if isBoundary {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func (g *ImportGraph) boundaryChain(path string) []string {
+	start := canonicalPath(path)
+	if node, ok := g.Nodes[start]; !ok || !node.loaded {
+		return nil
+	}
+
+	previous := map[string]string{start: ""}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if g.Nodes[current].IsClient {
+			chain := []string{current}
+			for next := previous[current]; next != ""; next = previous[next] {
+				chain = append(chain, next)
+			}
+			return chain
+		}
+
+		for _, importer := range g.Nodes[current].Importers {
+			if _, seen := previous[importer]; seen {
+				continue
+			}
+			previous[importer] = current
+			queue = append(queue, importer)
+		}
+	}
+
+	return nil
+}
+
+func isPackageSpecifier(specifier string) bool {
+	if strings.HasPrefix(specifier, ".") || strings.HasPrefix(specifier, "/") || strings.HasPrefix(specifier, "#") || strings.Contains(specifier, ":") {
+		return false
+	}
+	return !nodeBuiltins[packageName(specifier)]
+}
+
+func packageName(specifier string) string {
+	parts := strings.Split(specifier, "/")
+	if strings.HasPrefix(specifier, "@") && len(parts) > 1 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+var nodeBuiltins = map[string]bool{
+	"assert": true, "buffer": true, "child_process": true, "cluster": true, "crypto": true,
+	"dgram": true, "dns": true, "events": true, "fs": true, "http": true, "http2": true,
+	"https": true, "module": true, "net": true, "os": true, "path": true, "perf_hooks": true,
+	"process": true, "querystring": true, "readline": true, "stream": true, "string_decoder": true,
+	"timers": true, "tls": true, "tty": true, "url": true, "util": true, "v8": true, "vm": true,
+	"worker_threads": true, "zlib": true,
+}
+
+func displayPath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(cwd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		if rel, err := filepath.Rel(canonicalPath(cwd), path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+		return path
+	}
+	return rel
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestIsPackageSpecifier(t *testing.T) {
+	tests := map[string]bool{
+		"react":            true,
+		"@scope/ui/button": true,
+		"./Button":         false,
+		"/abs/path":        false,
+		"#lib/db":          false,
+		"node:fs":          false,
+		"fs":               false,
+	}
+	for specifier, want := range tests {
+		if got := isPackageSpecifier(specifier); got != want {
+			t.Errorf("isPackageSpecifier(%q) = %v, want %v", specifier, got, want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"html/template"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	htmlGraphRow   = 28
+	htmlGraphWidth = 960
+)
+
+type htmlReport struct {
+	Findings int
+	Files    int
+	Rules    []string
+	Groups   []htmlGroup
+	Graph    htmlGraph
+}
+
+type htmlGroup struct {
+	Name     string
+	Findings []Finding
+}
+
+type htmlGraph struct {
+	Height  int
+	Servers []htmlGraphNode
+	Clients []htmlGraphNode
+	Edges   []htmlGraphEdge
+}
+
+type htmlGraphNode struct {
+	File string
+	Y    int
+}
+
+type htmlGraphEdge struct {
+	Y1, Y2 int
+	Source string
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>RSC boundary report</title>
+<style>
+body { font: 14px/1.4 system-ui, sans-serif; margin: 2em; color: #212529; }
+h1 { font-size: 1.5em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1em; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #dee2e6; vertical-align: top; }
+td.content { font-family: ui-monospace, monospace; white-space: pre; }
+tr.error td.severity { color: #c92a2a; }
+tr.warning td.severity { color: #e67700; }
+details { margin-bottom: 0.5em; }
+summary { cursor: pointer; font-weight: 600; }
+.controls { margin: 1em 0; }
+.controls input { width: 24em; }
+svg text { font: 12px ui-monospace, monospace; }
+svg .client rect { fill: #ffd8a8; stroke: #e8590c; }
+svg .server rect { fill: #e7f5ff; stroke: #1c7ed6; }
+svg line { stroke: #868e96; }
+</style>
+</head>
+<body>
+<h1>RSC boundary report</h1>
+<p>{{.Findings}} findings in {{.Files}} files.</p>
+
+<h2>Findings</h2>
+<div class="controls">
+<input id="filter" type="search" placeholder="Filter by file, component or message">
+<select id="rule"><option value="">All rules</option>{{range .Rules}}<option>{{.}}</option>{{end}}</select>
+</div>
+{{range .Groups}}
+<details open class="group">
+<summary>{{.Name}} (<span class="count">{{len .Findings}}</span>)</summary>
+<table>
+<tr><th>Location</th><th>Rule</th><th>Severity</th><th>Component</th><th>Client file</th><th>Code</th></tr>
+{{range .Findings}}<tr class="finding {{.Severity}}" data-rule="{{.Rule}}">
+<td>{{.File}}:{{.Line}}</td><td>{{.Rule}}</td><td class="severity">{{.Severity}}</td><td>{{.Component}}</td><td>{{.ResolvedClientFile}}</td><td class="content">{{if .Message}}{{.Message}}{{else}}{{.Content}}{{end}}</td>
+</tr>
+{{end}}</table>
+</details>
+{{end}}
+
+<h2>Boundary graph</h2>
+{{if .Graph.Edges}}<svg width="{{.Graph.Width}}" height="{{.Graph.Height}}" xmlns="http://www.w3.org/2000/svg">
+{{range .Graph.Edges}}<line x1="400" y1="{{.Y1}}" x2="560" y2="{{.Y2}}"><title>{{.Source}}</title></line>
+{{end}}{{range .Graph.Servers}}<g class="server"><rect x="0" y="{{.RectY}}" width="400" height="22" rx="3"></rect><text x="6" y="{{.Y}}" dy="4">{{.File}}</text></g>
+{{end}}{{range .Graph.Clients}}<g class="client"><rect x="560" y="{{.RectY}}" width="400" height="22" rx="3"></rect><text x="566" y="{{.Y}}" dy="4">{{.File}}</text></g>
+{{end}}</svg>{{else}}<p>No client components are rendered from server files.</p>{{end}}
+
+<script>
+(function () {
+  var filter = document.getElementById('filter');
+  var rule = document.getElementById('rule');
+  function apply() {
+    var text = filter.value.toLowerCase();
+    document.querySelectorAll('details.group').forEach(function (group) {
+      var visible = 0;
+      group.querySelectorAll('tr.finding').forEach(function (row) {
+        var show = row.textContent.toLowerCase().indexOf(text) >= 0 && (!rule.value || row.dataset.rule === rule.value);
+        row.style.display = show ? '' : 'none';
+        if (show) visible++;
+      });
+      group.querySelector('.count').textContent = visible;
+      group.style.display = visible ? '' : 'none';
+    });
+  }
+  filter.addEventListener('input', apply);
+  rule.addEventListener('change', apply);
+})();
+</script>
+</body>
+</html>
+`))
+
+func (g htmlGraph) Width() int {
+	return htmlGraphWidth
+}
+
+func (n htmlGraphNode) RectY() int {
+	return n.Y - 11
+}
+
+func writeHTMLReport(w io.Writer, result *ScanResult) error {
+	report := htmlReport{Findings: len(result.Findings)}
+
+	files := make(map[string]bool)
+	rules := make(map[string]bool)
+	groups := make(map[string][]Finding)
+	for _, f := range result.Findings {
+		files[f.File] = true
+		rules[f.Rule] = true
+
+		group := f.Route
+		if group == "" {
+			group = filepath.ToSlash(filepath.Dir(f.File))
+		}
+		groups[group] = append(groups[group], f)
+	}
+	report.Files = len(files)
+
+	for rule := range rules {
+		report.Rules = append(report.Rules, rule)
+	}
+	sort.Strings(report.Rules)
+
+	for name, findings := range groups {
+		report.Groups = append(report.Groups, htmlGroup{Name: name, Findings: findings})
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		return report.Groups[i].Name < report.Groups[j].Name
+	})
+
+	report.Graph = newHTMLGraph(result.Findings)
+	return htmlReportTemplate.Execute(w, report)
+}
+
+func newHTMLGraph(findings []Finding) htmlGraph {
+	type edge struct{ from, to string }
+	sources := make(map[edge]string)
+	servers := make(map[string]bool)
+	clients := make(map[string]bool)
+	for _, f := range findings {
+		if f.Rule != RuleClientUsage || f.ResolvedClientFile == "" {
+			continue
+		}
+		e := edge{displayPath(f.File), displayPath(f.ResolvedClientFile)}
+		sources[e] = f.ImportSource
+		servers[e.from] = true
+		clients[e.to] = true
+	}
+
+	var graph htmlGraph
+	rows := make(map[string]int)
+	place := func(set map[string]bool) []htmlGraphNode {
+		var files []string
+		for file := range set {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+
+		var nodes []htmlGraphNode
+		for i, file := range files {
+			y := i*htmlGraphRow + htmlGraphRow/2
+			nodes = append(nodes, htmlGraphNode{File: file, Y: y})
+			rows[file] = y
+		}
+		if height := len(files) * htmlGraphRow; height > graph.Height {
+			graph.Height = height
+		}
+		return nodes
+	}
+	graph.Servers = place(servers)
+	serverRows := rows
+	rows = make(map[string]int)
+	graph.Clients = place(clients)
+
+	for e, source := range sources {
+		graph.Edges = append(graph.Edges, htmlGraphEdge{Y1: serverRows[e.from], Y2: rows[e.to], Source: source})
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Y1 != graph.Edges[j].Y1 {
+			return graph.Edges[i].Y1 < graph.Edges[j].Y1
+		}
+		return graph.Edges[i].Y2 < graph.Edges[j].Y2
+	})
+	return graph
+}
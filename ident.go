@@ -0,0 +1,22 @@
+package main
+
+import "regexp"
+
+const (
+	identStartChars = `\p{L}\p{Nl}$_`
+	identPartChars  = identStartChars + `\p{Mn}\p{Mc}\p{Nd}\p{Pc}\x{200C}\x{200D}`
+	identPattern    = `[` + identStartChars + `][` + identPartChars + `]*`
+)
+
+var (
+	identStartRegex = regexp.MustCompile(`^[` + identStartChars + `]`)
+	identPartRegex  = regexp.MustCompile(`^[` + identPartChars + `]`)
+)
+
+func jsxTagPattern(name string) string {
+	return `<\s*` + regexp.QuoteMeta(name) + `(?:[^` + identPartChars + `]|$)`
+}
+
+func jsxPropReferencePattern(name string) string {
+	return `[` + identPartChars + `]\s*=\s*\{\s*(` + regexp.QuoteMeta(name) + `)\s*\}`
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type ImportMap struct {
+	Path    string
+	Imports map[string]string
+	Scopes  map[string]map[string]string
+}
+
+type importMapFile struct {
+	Imports   map[string]string            `json:"imports"`
+	Scopes    map[string]map[string]string `json:"scopes"`
+	ImportMap string                       `json:"importMap"`
+}
+
+type importMapMemo struct {
+	mu   sync.Mutex
+	dirs map[string]string
+	maps map[string]*importMapEntry
+}
+
+type importMapEntry struct {
+	importMap *ImportMap
+	err       error
+}
+
+func newImportMapMemo() *importMapMemo {
+	return &importMapMemo{dirs: make(map[string]string), maps: make(map[string]*importMapEntry)}
+}
+
+func (m *importMapMemo) find(baseDir string) string {
+	if m == nil {
+		return findUp(baseDir, "deno.json", "deno.jsonc")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path, ok := m.dirs[baseDir]
+	if !ok {
+		path = findUp(baseDir, "deno.json", "deno.jsonc")
+		m.dirs[baseDir] = path
+	}
+	return path
+}
+
+func (m *importMapMemo) parse(path string) (*ImportMap, error) {
+	if m == nil {
+		return parseImportMap(path)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.maps[path]
+	if !ok {
+		importMap, err := parseImportMap(path)
+		entry = &importMapEntry{importMap: importMap, err: err}
+		m.maps[path] = entry
+	}
+	return entry.importMap, entry.err
+}
+
+// covers reports whether path is a deno.json or an import map file that
+// loaded import maps may have been read from.
+func (m *importMapMemo) covers(path string) bool {
+	if name := filepath.Base(path); name == "deno.json" || name == "deno.jsonc" {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range m.maps {
+		if entry.importMap != nil && absPath(entry.importMap.Path) == absPath(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *importMapMemo) forget() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dirs = make(map[string]string)
+	m.maps = make(map[string]*importMapEntry)
+}
+
+func loadImportMap(baseDir string, config *Config) (*ImportMap, error) {
+	if config.ImportMap != "" {
+		return config.importMaps.parse(config.ImportMap)
+	}
+
+	denoPath := config.importMaps.find(baseDir)
+	config.dependsOnProbes(baseDir, denoPath, "deno.json", "deno.jsonc")
+	if denoPath != "" {
+		return config.importMaps.parse(denoPath)
+	}
+
+	return nil, nil
+}
+
+func parseImportMap(path string) (*ImportMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file importMapFile
+	if err := json.Unmarshal(normalizeJSONC(data), &file); err != nil {
+		return nil, err
+	}
+
+	if file.ImportMap != "" && file.Imports == nil {
+		target := file.ImportMap
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		return parseImportMap(target)
+	}
+
+	return &ImportMap{
+		Path:    path,
+		Imports: file.Imports,
+		Scopes:  file.Scopes,
+	}, nil
+}
+
+func (m *ImportMap) Resolve(referrerDir, specifier string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+
+	dir := filepath.Dir(m.Path)
+
+	bestScope := ""
+	for scope := range m.Scopes {
+		if isWithin(referrerDir, filepath.Join(dir, scope)) && len(scope) > len(bestScope) {
+			bestScope = scope
+		}
+	}
+	if bestScope != "" {
+		if target, ok := matchImportMap(m.Scopes[bestScope], specifier); ok {
+			return localImportMapTarget(dir, target)
+		}
+	}
+
+	if target, ok := matchImportMap(m.Imports, specifier); ok {
+		return localImportMapTarget(dir, target)
+	}
+
+	return "", false
+}
+
+func matchImportMap(imports map[string]string, specifier string) (string, bool) {
+	if target, ok := imports[specifier]; ok {
+		return target, true
+	}
+
+	bestKey := ""
+	for key := range imports {
+		if strings.HasSuffix(key, "/") && strings.HasPrefix(specifier, key) && len(key) > len(bestKey) {
+			bestKey = key
+		}
+	}
+	if bestKey == "" {
+		return "", false
+	}
+
+	return imports[bestKey] + strings.TrimPrefix(specifier, bestKey), true
+}
+
+func localImportMapTarget(dir, target string) (string, bool) {
+	if strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") {
+		return filepath.Join(dir, target), true
+	}
+	if filepath.IsAbs(target) {
+		return target, true
+	}
+	return "", false
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadImportMap(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"deno/deno.json":          `{"imports": {"@ui/": "./src/ui/"}}`,
+		"deno/src/app/page.tsx":   "",
+		"html/import_map.json":    `{"imports": {"ui": "./ui/index.tsx"}, "scopes": {"./legacy/": {"ui": "./legacy/ui.tsx"}}}`,
+		"html/" + configFileName:  `{"importMap": "import_map.json"}`,
+		"html/legacy/page.tsx":    "",
+		"none/src/app/page.tsx":   "",
+		"nested/deno.jsonc":       "{\n  // comment\n  \"importMap\": \"./map.json\"\n}",
+		"nested/map.json":         `{"imports": {"lib/": "./vendor/lib/"}}`,
+		"nested/src/components/x": "",
+	})
+	path := func(name string) string {
+		return filepath.Join(root, filepath.FromSlash(name))
+	}
+
+	tests := []struct {
+		name      string
+		configDir string
+		baseDir   string
+		specifier string
+		want      string
+	}{
+		{name: "deno.json prefix", baseDir: "deno/src/app", specifier: "@ui/Button.tsx", want: path("deno/src/ui/Button.tsx")},
+		{name: "deno.jsonc importMap field", baseDir: "nested/src/components", specifier: "lib/a.ts", want: path("nested/vendor/lib/a.ts")},
+		{name: "config file importMap", configDir: "html", baseDir: "html", specifier: "ui", want: path("html/ui/index.tsx")},
+		{name: "config file importMap scope", configDir: "html", baseDir: "html/legacy", specifier: "ui", want: path("html/legacy/ui.tsx")},
+		{name: "no import map", baseDir: "none/src/app", specifier: "ui"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			if tt.configDir != "" {
+				if _, _, err := loadScanRoots(path(tt.configDir), path(tt.configDir+"/"+configFileName), true, config); err != nil {
+					t.Fatal(err)
+				}
+			}
+			importMap, err := loadImportMap(path(tt.baseDir), config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := importMap.Resolve(path(tt.baseDir), tt.specifier)
+			if got != tt.want {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.specifier, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportMapMemo(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"deno.json": `{"imports": {"a/": "./a/"}}`,
+		"src/x.tsx": "",
+	})
+	config := DefaultConfig()
+	dir := filepath.Join(root, "src")
+
+	first, err := loadImportMap(dir, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(root, "deno.json")); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := loadImportMap(dir, config)
+	if first == nil || second != first {
+		t.Fatalf("loadImportMap() = %p then %p, want the memoized import map twice", first, second)
+	}
+	if !config.importMaps.covers(filepath.Join(root, "deno.json")) {
+		t.Error("covers(deno.json) = false, want true")
+	}
+
+	config.importMaps.forget()
+	if third, _ := loadImportMap(dir, config); third != nil {
+		t.Errorf("loadImportMap() after forget() = %+v, want nil for the removed deno.json", third)
+	}
+}
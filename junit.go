@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+type junitReport struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnitReport(w io.Writer, result *ScanResult) error {
+	cases := make(map[string]*junitTestCase)
+	var names []string
+	testCase := func(file string) *junitTestCase {
+		name := filepath.ToSlash(displayPath(file))
+		if c, ok := cases[name]; ok {
+			return c
+		}
+		c := &junitTestCase{Name: name, ClassName: "go-rsc-boundary"}
+		cases[name] = c
+		names = append(names, name)
+		return c
+	}
+
+	for _, file := range result.Scanned {
+		testCase(file)
+	}
+	for _, f := range result.Findings {
+		c := testCase(f.File)
+		position := fmt.Sprintf("%s:%d", c.Name, f.Line)
+		if f.Column > 0 {
+			position += fmt.Sprintf(":%d", f.Column)
+		}
+		c.Failures = append(c.Failures, junitFailure{
+			Message: findingMessage(f),
+			Type:    f.Rule,
+			Text:    fmt.Sprintf("%s: %s: %s\n%s", position, f.Severity, findingMessage(f), f.Content),
+		})
+	}
+	sort.Strings(names)
+
+	suite := junitSuite{Name: "go-rsc-boundary"}
+	for _, name := range names {
+		c := cases[name]
+		suite.Cases = append(suite.Cases, *c)
+		suite.Tests++
+		if len(c.Failures) > 0 {
+			suite.Failures++
+		}
+	}
+	report := junitReport{Name: suite.Name, Tests: suite.Tests, Failures: suite.Failures, Suites: []junitSuite{suite}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestWriteJUnitReport(t *testing.T) {
+	result := &ScanResult{
+		Scanned: []string{"app/page.tsx", "app/layout.tsx", "lib/util.ts"},
+		Findings: []Finding{
+			{File: "app/page.tsx", Line: 3, Column: 7, Content: "  <Button />", Rule: RuleClientUsage, Severity: SeverityWarning, Component: "Button", ImportSource: "./Button"},
+			{File: "app/page.tsx", Line: 1, Content: "import x from './x'", Rule: RuleUnresolvedImport, Severity: SeverityError, Message: "unresolved import './x'"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeJUnitReport(&buf, result); err != nil {
+		t.Fatal(err)
+	}
+	var report junitReport
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Tests != 3 || report.Failures != 1 || len(report.Suites) != 1 {
+		t.Fatalf("report with %d tests, %d failures and %d suites, want 3, 1 and 1", report.Tests, report.Failures, len(report.Suites))
+	}
+
+	var names []string
+	var page junitTestCase
+	for _, c := range report.Suites[0].Cases {
+		names = append(names, c.Name)
+		if c.Name == "app/page.tsx" {
+			page = c
+		} else if len(c.Failures) > 0 {
+			t.Errorf("passing file %s has %d failures", c.Name, len(c.Failures))
+		}
+	}
+	if want := []string{"app/layout.tsx", "app/page.tsx", "lib/util.ts"}; len(names) != len(want) || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Errorf("test cases %v, want %v", names, want)
+	}
+
+	tests := []struct {
+		typ  string
+		text string
+	}{
+		{RuleClientUsage, "app/page.tsx:3:7: warning: client component <Button> from './Button' rendered in a server file\n  <Button />"},
+		{RuleUnresolvedImport, "app/page.tsx:1: error: unresolved import './x'\nimport x from './x'"},
+	}
+	if len(page.Failures) != len(tests) {
+		t.Fatalf("app/page.tsx has %d failures, want %d", len(page.Failures), len(tests))
+	}
+	for i, tt := range tests {
+		if f := page.Failures[i]; f.Type != tt.typ || f.Text != tt.text {
+			t.Errorf("failure %d = %s %q, want %s %q", i, f.Type, f.Text, tt.typ, tt.text)
+		}
+	}
+}
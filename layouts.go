@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+var layoutFileRegex = regexp.MustCompile(`^layout\.(tsx|jsx|ts|js)$`)
+var pageFileRegex = regexp.MustCompile(`^page\.(tsx|jsx|ts|js|mdx)$`)
+
+type LayoutWeight struct {
+	Layout           string   `json:"layout"`
+	Route            string   `json:"route"`
+	Pages            int      `json:"pages"`
+	ClientComponents []string `json:"clientComponents"`
+	OwnBytes         int64    `json:"ownBytes"`
+	InheritedBytes   int64    `json:"inheritedBytes"`
+}
+
+func runLayoutsCommand(args []string) error {
+	command := newGraphCommand("layouts")
+	config, graph, err := command.parse(args)
+	if err != nil {
+		return err
+	}
+
+	weights := layoutWeights(graph, config, *command.verbose)
+
+	return command.write(os.Stdout, weights, func(w io.Writer) error {
+		return writeLayoutsText(w, weights)
+	})
+}
+
+func appRouterFiles(graph *ImportGraph, pattern *regexp.Regexp) []string {
+	var paths []string
+	for path, node := range graph.Nodes {
+		if node.loaded && pattern.MatchString(filepath.Base(path)) && appDir(path) != "" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func layoutWeights(graph *ImportGraph, config *Config, verbose bool) []LayoutWeight {
+	layouts := appRouterFiles(graph, layoutFileRegex)
+	pages := appRouterFiles(graph, pageFileRegex)
+	sortByDepth(layouts)
+
+	closures := make(map[string]map[string]int64)
+	components := make(map[string][]string)
+	for _, layout := range layouts {
+		components[layout], closures[layout] = renderedClientClosure(graph, layout, config, verbose)
+	}
+
+	var weights []LayoutWeight
+	for _, layout := range layouts {
+		dir := filepath.Dir(layout)
+		weight := LayoutWeight{
+			Layout:           displayPath(layout),
+			Route:            routeForDir(dir),
+			ClientComponents: components[layout],
+			OwnBytes:         sumSizes(closures[layout]),
+		}
+
+		inherited := make(map[string]int64)
+		for _, other := range layouts {
+			if isWithin(dir, filepath.Dir(other)) {
+				for path, size := range closures[other] {
+					inherited[path] = size
+				}
+			}
+		}
+		weight.InheritedBytes = sumSizes(inherited)
+
+		for _, page := range pages {
+			if isWithin(filepath.Dir(page), dir) {
+				weight.Pages++
+			}
+		}
+
+		weights = append(weights, weight)
+	}
+
+	return weights
+}
+
+func renderedClientClosure(graph *ImportGraph, path string, config *Config, verbose bool) ([]string, map[string]int64) {
+	closure := make(map[string]int64)
+	components := []string{}
+
+	node := graph.load(path, config, verbose)
+	if node.IsClient {
+		for _, n := range graph.closure(path, config, verbose) {
+			closure[n.Path] = n.Size
+		}
+		return []string{"(layout is a client component)"}, closure
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return components, closure
+	}
+
+	for _, edge := range node.Imports {
+		if !graph.load(edge.Target, config, verbose).IsClient {
+			continue
+		}
+		rendered := false
+		for _, spec := range edge.Specifiers {
+			if containsJSXTag(string(content), spec) {
+				components = append(components, spec)
+				rendered = true
+			}
+		}
+		if !rendered {
+			continue
+		}
+		for _, n := range graph.importClosure(edge.Target, edge.Names, config, verbose) {
+			closure[n.Path] = n.Size
+		}
+	}
+
+	return components, closure
+}
+
+func sortByDepth(paths []string) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		return strings.Count(paths[i], string(filepath.Separator)) < strings.Count(paths[j], string(filepath.Separator))
+	})
+}
+
+func sumSizes(sizes map[string]int64) int64 {
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	return total
+}
+
+func appDir(path string) string {
+	dir := filepath.Dir(path)
+	for {
+		if filepath.Base(dir) == "app" {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func routeForDir(dir string) string {
+	root := appDir(filepath.Join(dir, "_"))
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return "/"
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(filepath.ToSlash(rel), "/") {
+		if strings.HasPrefix(segment, "(") && strings.HasSuffix(segment, ")") {
+			continue
+		}
+		if strings.HasPrefix(segment, "@") {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+func writeLayoutsText(w io.Writer, weights []LayoutWeight) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "LAYOUT\tROUTE\tPAGES\tCLIENT COMPONENTS\tOWN BYTES\tINHERITED BYTES")
+	for _, weight := range weights {
+		names := strings.Join(weight.ClientComponents, ", ")
+		if names == "" {
+			names = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%d\t%d\n", weight.Layout, weight.Route, weight.Pages, names, weight.OwnBytes, weight.InheritedBytes)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var lazyImportRegex = regexp.MustCompile(`(?:const|let|var)\s+(` + identPattern + `)\s*=\s*(?:React\s*\.\s*)?(?:dynamic|lazy)\s*\(\s*(?:async\s*)?\(\s*\)\s*=>\s*import\s*\(\s*['"]([^'"]+)['"]\s*\)(?:\s*\.then\s*\(\s*\(?\s*` + identPattern + `\s*\)?\s*=>\s*(?:\(\s*)?(?:\{\s*default\s*:\s*)?` + identPattern + `\s*\.\s*(` + identPattern + `))?`)
+
+func parseLazyImports(lines []string) []ImportInfo {
+	content := strings.Join(lines, "\n")
+
+	var imports []ImportInfo
+	for _, match := range lazyImportRegex.FindAllStringSubmatchIndex(content, -1) {
+		name := "default"
+		if match[6] >= 0 {
+			name = content[match[6]:match[7]]
+		}
+		imports = append(imports, ImportInfo{
+			Source:     content[match[4]:match[5]],
+			Specifiers: []string{content[match[2]:match[3]]},
+			Names:      []string{name},
+			Line:       strings.Count(content[:match[0]], "\n") + 1,
+		})
+	}
+	return imports
+}
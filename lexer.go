@@ -0,0 +1,264 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenTemplate
+	tokenNumber
+	tokenRegexp
+	tokenPunct
+)
+
+type token struct {
+	kind      tokenKind
+	text      string
+	line      int
+	offset    int
+	lineStart bool
+}
+
+type lexer struct {
+	src       string
+	pos       int
+	line      int
+	lineStart bool
+	braces    int
+	templates []int
+	tokens    []token
+}
+
+func tokenize(src string) []token {
+	l := &lexer{src: src, line: 1, lineStart: true}
+	l.run()
+	return l.tokens
+}
+
+func (l *lexer) run() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.pos++
+			l.line++
+			l.lineStart = true
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			l.pos++
+		case c == '/' && l.peek(1) == '/':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case c == '/' && l.peek(1) == '*':
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				end = len(l.src) - l.pos - 2
+			}
+			l.line += strings.Count(l.src[l.pos:l.pos+2+end], "\n")
+			l.pos += end + 4
+		case c == '\'' || c == '"':
+			l.string(c)
+		case c == '`':
+			l.pos++
+			l.template(l.pos - 1)
+		case c == '/' && l.regexpAllowed():
+			l.regexp()
+		case isIdentStart(l.src[l.pos:]):
+			start := l.pos
+			for l.pos < len(l.src) && isIdentPart(l.src[l.pos:]) {
+				_, size := utf8.DecodeRuneInString(l.src[l.pos:])
+				l.pos += size
+			}
+			l.emit(tokenIdent, start)
+		case c >= '0' && c <= '9':
+			start := l.pos
+			for l.pos < len(l.src) && (isIdentPart(l.src[l.pos:]) || l.src[l.pos] == '.') {
+				l.pos++
+			}
+			l.emit(tokenNumber, start)
+		case c == '{':
+			l.braces++
+			l.pos++
+			l.emit(tokenPunct, l.pos-1)
+		case c == '}':
+			if n := len(l.templates); n > 0 && l.templates[n-1] == l.braces {
+				l.templates = l.templates[:n-1]
+				l.pos++
+				l.template(l.pos - 1)
+				continue
+			}
+			l.braces--
+			l.pos++
+			l.emit(tokenPunct, l.pos-1)
+		default:
+			l.pos++
+			l.emit(tokenPunct, l.pos-1)
+		}
+	}
+}
+
+func (l *lexer) peek(offset int) byte {
+	if l.pos+offset < len(l.src) {
+		return l.src[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *lexer) emit(kind tokenKind, start int) {
+	l.tokens = append(l.tokens, token{
+		kind:      kind,
+		text:      l.src[start:l.pos],
+		line:      l.line,
+		offset:    start,
+		lineStart: l.lineStart && len(l.templates) == 0,
+	})
+	l.lineStart = false
+}
+
+func (l *lexer) string(quote byte) {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == '\\' {
+			l.pos += 2
+			continue
+		}
+		if c == '\n' {
+			break
+		}
+		l.pos++
+		if c == quote {
+			break
+		}
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+	l.emit(tokenString, start)
+}
+
+func (l *lexer) template(start int) {
+	line := l.line
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\\':
+			l.pos += 2
+			continue
+		case c == '\n':
+			l.line++
+		case c == '`':
+			l.pos++
+			l.emitTemplate(start, line)
+			return
+		case c == '$' && l.peek(1) == '{':
+			l.pos += 2
+			l.emitTemplate(start, line)
+			l.templates = append(l.templates, l.braces)
+			return
+		}
+		l.pos++
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+	l.emitTemplate(start, line)
+}
+
+func (l *lexer) emitTemplate(start, line int) {
+	l.tokens = append(l.tokens, token{kind: tokenTemplate, text: l.src[start:l.pos], line: line, offset: start})
+	l.lineStart = false
+}
+
+func (l *lexer) regexpAllowed() bool {
+	if len(l.tokens) == 0 {
+		return true
+	}
+	previous := l.tokens[len(l.tokens)-1]
+	switch previous.kind {
+	case tokenIdent:
+		switch previous.text {
+		case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+			return true
+		}
+		return false
+	case tokenPunct:
+		return previous.text != ")" && previous.text != "]" && previous.text != "}" && previous.text != "<"
+	}
+	return false
+}
+
+func (l *lexer) regexp() {
+	start := l.pos
+	l.pos++
+	inClass := false
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == '\\' {
+			l.pos += 2
+			continue
+		}
+		if c == '\n' {
+			break
+		}
+		l.pos++
+		if c == '[' {
+			inClass = true
+		} else if c == ']' {
+			inClass = false
+		} else if c == '/' && !inClass {
+			for l.pos < len(l.src) && isIdentPart(l.src[l.pos:]) {
+				l.pos++
+			}
+			break
+		}
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+	l.emit(tokenRegexp, start)
+}
+
+// codeMask reports for every byte of src whether it is code, as opposed to a
+// comment, a string literal or the static text of a template literal. A
+// string that is not closed on its line is kept as code: in JSX that is an
+// apostrophe in text ("Don't"), not a literal.
+func codeMask(src string, tokens []token) []bool {
+	code := make([]bool, len(src))
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenTemplate:
+			continue
+		case tokenString:
+			if len(t.text) > 1 && t.text[len(t.text)-1] == t.text[0] {
+				continue
+			}
+		}
+		for i := t.offset; i < t.offset+len(t.text); i++ {
+			code[i] = true
+		}
+	}
+	return code
+}
+
+func isIdentStart(s string) bool {
+	c := s[0]
+	if c < utf8.RuneSelf {
+		return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+	return identStartRegex.MatchString(s)
+}
+
+func isIdentPart(s string) bool {
+	c := s[0]
+	if c < utf8.RuneSelf {
+		return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+	return identPartRegex.MatchString(s)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	CyclesIgnore = "ignore"
+	CyclesWarn   = "warn"
+)
+
+type TraversalLimits struct {
+	MaxClosureDepth int    `json:"maxClosureDepth"`
+	MaxReexportHops int    `json:"maxReexportHops"`
+	Cycles          string `json:"cycles"`
+}
+
+var defaultTraversalLimits = TraversalLimits{
+	MaxClosureDepth: 256,
+	MaxReexportHops: 16,
+	Cycles:          CyclesIgnore,
+}
+
+func (l *TraversalLimits) merge(other *TraversalLimits) {
+	if other.MaxClosureDepth > 0 {
+		l.MaxClosureDepth = other.MaxClosureDepth
+	}
+	if other.MaxReexportHops > 0 {
+		l.MaxReexportHops = other.MaxReexportHops
+	}
+	if other.Cycles != "" {
+		l.Cycles = other.Cycles
+	}
+}
+
+type byteSize int64
+
+func (s *byteSize) String() string {
+	return strconv.FormatInt(int64(*s), 10)
+}
+
+func (s *byteSize) Set(value string) error {
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(value, "K"):
+		multiplier, value = 1<<10, strings.TrimSuffix(value, "K")
+	case strings.HasSuffix(value, "M"):
+		multiplier, value = 1<<20, strings.TrimSuffix(value, "M")
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("expected a size in bytes with an optional K or M suffix, got %q", value)
+	}
+	*s = byteSize(n * multiplier)
+	return nil
+}
+
+func (c *Config) oversized(path string, size int64, verbose bool) bool {
+	if c.MaxFileSize <= 0 || size <= c.MaxFileSize {
+		return false
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Skipping %s (%d bytes, over -max-file-size %d)\n", path, size, c.MaxFileSize)
+	}
+	return true
+}
+
+func (g *ImportGraph) diagnose(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if g.diagnosed == nil {
+		g.diagnosed = make(map[string]bool)
+	}
+	if g.diagnosed[message] {
+		return
+	}
+	g.diagnosed[message] = true
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", message)
+}
+
+func formatCycle(cycle []string) string {
+	paths := make([]string, len(cycle))
+	for i, path := range cycle {
+		paths[i] = displayPath(path)
+	}
+	return strings.Join(paths, " -> ")
+}
+
+func importCycle(parent map[string]string, from, to string) []string {
+	cycle := []string{to}
+	for path := from; path != ""; path = parent[path] {
+		cycle = append([]string{path}, cycle...)
+		if path == to {
+			return cycle
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Lockfile struct {
+	Path     string
+	Versions map[string][]string
+}
+
+func loadLockfile(dir string) (*Lockfile, error) {
+	path := findUp(absPath(dir), "package-lock.json", "yarn.lock", "pnpm-lock.yaml")
+	if path == "" {
+		return &Lockfile{Versions: make(map[string][]string)}, nil
+	}
+
+	lock := &Lockfile{Path: path, Versions: make(map[string][]string)}
+
+	var err error
+	switch filepath.Base(path) {
+	case "package-lock.json":
+		err = lock.parseNPM(path)
+	case "yarn.lock":
+		err = lock.parseYarn(path)
+	case "pnpm-lock.yaml":
+		err = lock.parsePNPM(path)
+	}
+	return lock, err
+}
+
+func (l *Lockfile) add(name, version string) {
+	if name == "" || version == "" {
+		return
+	}
+	for _, existing := range l.Versions[name] {
+		if existing == version {
+			return
+		}
+	}
+	l.Versions[name] = append(l.Versions[name], version)
+}
+
+func (l *Lockfile) parseNPM(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return err
+	}
+
+	for key, pkg := range lock.Packages {
+		idx := strings.LastIndex(key, "node_modules/")
+		if idx < 0 {
+			continue
+		}
+		l.add(key[idx+len("node_modules/"):], pkg.Version)
+	}
+	for name, pkg := range lock.Dependencies {
+		l.add(name, pkg.Version)
+	}
+	return nil
+}
+
+func (l *Lockfile) parseYarn(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") && strings.HasSuffix(line, ":") {
+			names = nil
+			for _, key := range strings.Split(strings.TrimSuffix(line, ":"), ",") {
+				key = strings.Trim(strings.TrimSpace(key), `"`)
+				if name, _ := splitPackageVersion(key); name != "" {
+					names = append(names, name)
+				}
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "version") {
+			version := strings.TrimPrefix(trimmed, "version")
+			version = strings.Trim(strings.TrimSpace(strings.TrimPrefix(version, ":")), `"`)
+			for _, name := range names {
+				l.add(name, version)
+			}
+			names = nil
+		}
+	}
+	return scanner.Err()
+}
+
+func (l *Lockfile) parsePNPM(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	inPackages := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			inPackages = strings.TrimSpace(line) == "packages:"
+			continue
+		}
+		if !inPackages || strings.HasPrefix(line, "    ") || !strings.HasSuffix(line, ":") {
+			continue
+		}
+
+		key := strings.Trim(strings.TrimSuffix(strings.TrimSpace(line), ":"), `'"`)
+		key = strings.TrimPrefix(key, "/")
+		if idx := strings.Index(key, "("); idx >= 0 {
+			key = key[:idx]
+		}
+
+		name, version := splitPackageVersion(key)
+		if name == "" {
+			if idx := strings.LastIndex(key, "/"); idx > 0 {
+				name, version = key[:idx], key[idx+1:]
+			}
+		}
+		l.add(name, version)
+	}
+	return scanner.Err()
+}
+
+func splitPackageVersion(key string) (string, string) {
+	idx := strings.LastIndex(key, "@")
+	if idx <= 0 {
+		return "", ""
+	}
+	version := strings.TrimPrefix(key[idx+1:], "npm:")
+	return key[:idx], version
+}
+
+type installedPackage struct {
+	Version string      `json:"version"`
+	License interface{} `json:"license"`
+}
+
+func readInstalledPackage(dir, name string) *installedPackage {
+	path := findUp(absPath(dir), filepath.Join("node_modules", name, "package.json"))
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var pkg installedPackage
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+	return &pkg
+}
+
+func (p *installedPackage) license() string {
+	switch license := p.License.(type) {
+	case string:
+		return license
+	case map[string]interface{}:
+		if licenseType, ok := license["type"].(string); ok {
+			return licenseType
+		}
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var loopCallRegex = regexp.MustCompile(`\.(map|flatMap)\s*\(`)
+
+func findLoopRenders(filePath, text string, lines []string, components map[string]clientImport) []Finding {
+	containers := findJSXContainers(text)
+
+	type site struct {
+		start     int
+		component string
+	}
+	seen := make(map[site]bool)
+	var findings []Finding
+
+	names := make([]string, 0, len(components))
+	for component := range components {
+		names = append(names, component)
+	}
+	sort.Strings(names)
+
+	for _, component := range names {
+		tagRegex := regexp.MustCompile(jsxTagPattern(component))
+		for _, loc := range tagRegex.FindAllStringIndex(text, -1) {
+			for _, container := range containers {
+				if container.start >= loc[0] || loc[0] >= container.end {
+					continue
+				}
+				body := text[container.start+1 : loc[0]]
+				match := loopCallRegex.FindStringSubmatchIndex(body)
+				if match == nil {
+					continue
+				}
+
+				s := site{container.start, component}
+				if seen[s] {
+					continue
+				}
+				seen[s] = true
+
+				line := lineAt(text, container.start+1+match[0])
+				receiver := loopReceiver(body[:match[0]])
+				findings = append(findings, Finding{
+					File:     filePath,
+					Line:     line,
+					Content:  lines[line-1],
+					Rule:     RuleClientInLoop,
+					Severity: SeverityWarning,
+					Message: fmt.Sprintf("client component <%s> rendered inside %s.%s() (line %d)",
+						component, receiver, body[match[2]:match[3]], lineAt(text, loc[0])),
+					Component:          component,
+					ImportSource:       components[component].Source,
+					ResolvedClientFile: components[component].Resolved,
+				})
+			}
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+func loopReceiver(prefix string) string {
+	prefix = strings.TrimSpace(prefix)
+	start := len(prefix)
+	for start > 0 && (isIdentByte(prefix[start-1]) || prefix[start-1] == '.' || prefix[start-1] == '?') {
+		start--
+	}
+	if start == len(prefix) {
+		return "(...)"
+	}
+	return prefix[start:]
+}
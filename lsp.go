@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	lspSeverityError   = 1
+	lspSeverityWarning = 2
+)
+
+type lspRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type lspResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+}
+
+type lspErrorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   lspError        `json:"error"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type lspTextDocument struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type lspDocumentParams struct {
+	TextDocument   lspTextDocument `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	Changes []struct {
+		URI string `json:"uri"`
+	} `json:"changes"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspPublishDiagnostics struct {
+	URI         string          `json:"uri"`
+	Diagnostics []lspDiagnostic `json:"diagnostics"`
+}
+
+type lspServer struct {
+	state   *watchState
+	verbose bool
+	open    map[string]string
+	out     io.Writer
+}
+
+func runLSPCommand(args []string) error {
+	command := newGraphCommand("lsp")
+	enable := command.flags.String("enable", "", "comma-separated opt-in rules to enable ("+RuleClientInLoop+", "+RuleServerAction+", "+RuleMissingClientDirective+", "+RuleNonSerializableProp+", "+RuleBrowserAPI+", "+RuleImportCasing+")")
+	command.flags.Parse(args)
+
+	config := DefaultConfig()
+	config.Flow = *command.flow
+	config.Enable = splitList(*enable)
+	roots, _, err := loadScanRoots(*command.path, *command.confPath, isFlagSet(command.flags, "path"), config)
+	if err != nil {
+		return err
+	}
+
+	server := &lspServer{
+		state:   &watchState{roots: roots, verbose: *command.verbose, files: make(map[string]*ScanResult)},
+		verbose: *command.verbose,
+		open:    make(map[string]string),
+		out:     os.Stdout,
+	}
+	return server.serve(os.Stdin)
+}
+
+func (s *lspServer) serve(in io.Reader) error {
+	reader := bufio.NewReader(in)
+	for {
+		data, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req lspRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			if err := writeMessage(s.out, lspErrorResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: lspError{Code: -32700, Message: err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var params lspDocumentParams
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params, &params)
+		}
+		path := uriPath(params.TextDocument.URI)
+
+		switch req.Method {
+		case "initialize":
+			s.state.rescan(nil)
+			err = s.respond(req.ID, map[string]interface{}{
+				"capabilities": map[string]interface{}{
+					"textDocumentSync": map[string]interface{}{"openClose": true, "change": 1, "save": true},
+				},
+				"serverInfo": map[string]string{"name": "go-rsc-boundary"},
+			})
+		case "textDocument/didOpen":
+			s.open[path] = params.TextDocument.Text
+			err = s.publish(path)
+		case "textDocument/didChange":
+			if n := len(params.ContentChanges); n > 0 {
+				s.open[path] = params.ContentChanges[n-1].Text
+			}
+			err = s.publish(path)
+		case "textDocument/didSave":
+			err = s.changed(map[string]bool{path: true})
+		case "workspace/didChangeWatchedFiles":
+			changed := make(map[string]bool)
+			for _, change := range params.Changes {
+				changed[uriPath(change.URI)] = true
+			}
+			err = s.changed(changed)
+		case "textDocument/didClose":
+			delete(s.open, path)
+			err = writeMessage(s.out, lspNotification{JSONRPC: "2.0", Method: "textDocument/publishDiagnostics", Params: lspPublishDiagnostics{URI: params.TextDocument.URI, Diagnostics: []lspDiagnostic{}}})
+		case "shutdown":
+			err = s.respond(req.ID, nil)
+		case "exit":
+			return nil
+		default:
+			if len(req.ID) > 0 {
+				err = writeMessage(s.out, lspErrorResponse{JSONRPC: "2.0", ID: req.ID, Error: lspError{Code: -32601, Message: fmt.Sprintf("unknown method: %s", req.Method)}})
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *lspServer) respond(id json.RawMessage, result interface{}) error {
+	return writeMessage(s.out, lspResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *lspServer) changed(paths map[string]bool) error {
+	structural := false
+	for path := range paths {
+		if _, ok := s.state.files[path]; !ok {
+			structural = true
+		}
+	}
+	dirty := s.state.dependents(paths, structural)
+	s.state.rescan(dirty)
+
+	var open []string
+	for path := range s.open {
+		if dirty[path] {
+			open = append(open, path)
+		}
+	}
+	sort.Strings(open)
+	for _, path := range open {
+		if err := s.publish(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *lspServer) publish(path string) error {
+	text := s.open[path]
+	result := &ScanResult{}
+	if err := scanContent(path, []byte(text), rootConfig(s.state.roots, path), s.verbose, result); err != nil && s.verbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to scan %s: %v\n", path, err)
+	}
+
+	lines := splitLines(text)
+	diagnostics := []lspDiagnostic{}
+	for _, f := range result.Findings {
+		diagnostics = append(diagnostics, findingDiagnostic(f, lines))
+	}
+
+	return writeMessage(s.out, lspNotification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  lspPublishDiagnostics{URI: pathURI(path), Diagnostics: diagnostics},
+	})
+}
+
+func findingDiagnostic(f Finding, lines []string) lspDiagnostic {
+	line := ""
+	if f.Line-1 < len(lines) {
+		line = lines[f.Line-1]
+	}
+
+	start, end := 0, utf16Len(line)
+	if f.Column > 0 && f.Column-1 <= len(line) {
+		start = utf16Len(line[:f.Column-1])
+		stop := f.Column - 1 + identLen(line[f.Column-1:])
+		if f.EndColumn > f.Column && f.EndColumn-1 <= len(line) {
+			stop = f.EndColumn - 1
+		}
+		if stop > f.Column-1 {
+			end = utf16Len(line[:stop])
+		}
+	}
+
+	severity := lspSeverityWarning
+	if f.Severity == SeverityError {
+		severity = lspSeverityError
+	}
+
+	return lspDiagnostic{
+		Range: lspRange{
+			Start: lspPosition{Line: f.Line - 1, Character: start},
+			End:   lspPosition{Line: f.Line - 1, Character: end},
+		},
+		Severity: severity,
+		Code:     f.Rule,
+		Source:   "rsc-boundary",
+		Message:  findingMessage(f),
+	}
+}
+
+// identLen returns the byte length of the identifier at the start of s, for
+// findings that record no end column.
+func identLen(s string) int {
+	n := 0
+	for n < len(s) {
+		_, size := utf8.DecodeRuneInString(s[n:])
+		if !identPartRegex.MatchString(s[n : n+size]) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r == utf8.RuneError {
+			n++
+			continue
+		}
+		n += len(utf16.Encode([]rune{r}))
+	}
+	return n
+}
+
+func uriPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(parsed.Path)
+}
+
+func pathURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(absPath(path))}).String()
+}
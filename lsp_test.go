@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFindingDiagnosticRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		finding    Finding
+		start, end int
+	}{
+		{
+			name:    "matched span",
+			line:    "  return < Button />",
+			finding: Finding{Line: 1, Column: 10, EndColumn: 18},
+			start:   9, end: 17,
+		},
+		{
+			name:    "identifier without an end column",
+			line:    "  const [a, setA] = useState(0)",
+			finding: Finding{Line: 1, Column: 21},
+			start:   20, end: 28,
+		},
+		{
+			name:    "UTF-16 columns",
+			line:    "const 😀 = <Bütton />",
+			finding: Finding{Line: 1, Column: 14, EndColumn: 22},
+			start:   11, end: 18,
+		},
+		{
+			name:    "whole line without a column",
+			line:    "import x from './x'",
+			finding: Finding{Line: 1},
+			start:   0, end: 19,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := findingDiagnostic(tt.finding, []string{tt.line}).Range
+			if r.Start.Character != tt.start || r.End.Character != tt.end {
+				t.Errorf("range = %d-%d, want %d-%d", r.Start.Character, r.End.Character, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestLSPServer(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"Button.tsx": "'use client'\nexport function Button() { return null }\n",
+		"page.tsx":   "",
+	})
+	page := filepath.Join(root, "page.tsx")
+	text := "import { useState } from 'react'\nimport { Button } from './Button'\n" +
+		"export default function Page() {\n  const [a, setA] = useState(0)\n  return <Slot as={Button}>< Button /></Slot>\n}\n"
+
+	config := DefaultConfig()
+	config.Enable = []string{RuleMissingClientDirective}
+	var out bytes.Buffer
+	server := &lspServer{
+		state: &watchState{roots: []ScanRoot{{Path: root, Config: config}}, files: make(map[string]*ScanResult)},
+		open:  make(map[string]string),
+		out:   &out,
+	}
+
+	uri := pathURI(page)
+	in := frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]interface{}{}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]interface{}{
+			"textDocument": map[string]string{"uri": uri, "text": text},
+		}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didClose", "params": map[string]interface{}{
+			"textDocument": map[string]string{"uri": uri},
+		}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "exit"})
+	if err := server.serve(strings.NewReader(in)); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := readResponses(t, &out)
+	if len(responses) != 3 {
+		t.Fatalf("got %d messages, want 3", len(responses))
+	}
+
+	var got []string
+	for _, d := range responses[1]["params"].(map[string]interface{})["diagnostics"].([]interface{}) {
+		d := d.(map[string]interface{})
+		r := d["range"].(map[string]interface{})
+		start := r["start"].(map[string]interface{})
+		end := r["end"].(map[string]interface{})
+		got = append(got, strings.Join([]string{
+			d["code"].(string),
+			formatFloat(start["line"]), formatFloat(start["character"]), formatFloat(end["character"]),
+		}, ":"))
+	}
+	want := []string{
+		RuleMissingClientDirective + ":3:20:28",
+		RuleClientUsage + ":4:19:25",
+		RuleClientUsage + ":4:27:35",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnostics = %v, want %v", got, want)
+	}
+
+	if closed := responses[2]["params"].(map[string]interface{})["diagnostics"].([]interface{}); len(closed) != 0 {
+		t.Errorf("didClose published %d diagnostics, want 0", len(closed))
+	}
+}
+
+func formatFloat(v interface{}) string {
+	return strconv.FormatFloat(v.(float64), 'f', 0, 64)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -9,72 +10,801 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/conao3/go-rsc-boundary/rscboundary"
 )
 
 type Config struct {
-	Directives       []string
-	SearchExtensions []string
-	MaxReadBytes     int64
+	Directives         []string
+	SearchExtensions   []string
+	ExtraExtensions    []string
+	MaxReadBytes       int64
+	ExplainResolution  bool
+	TraceAliases       bool
+	Strict             bool
+	ImportMap          string
+	Project            string
+	Ignore             []string
+	Gitignore          bool
+	Enable             []string
+	Severities         map[string]string
+	GeneratedMarkers   []string
+	IncludeGenerated   bool
+	Flow               bool
+	FollowOutsideRoot  bool
+	IncludeNodeModules bool
+	OutsideBoundary    string
+	Limits             TraversalLimits
+	Jobs               int
+	Aliases            []PathAlias
+	Framework          string
+	Boundaries         []Boundary
+	MaxDepth           int
+	MaxFileSize        int64
+	ClientPackages     []string
+
+	stream         func(*ScanResult)
+	explainOutput  io.Writer
+	deps           map[string]bool
+	cache          *Cache
+	directives     *directiveMemo
+	exports        *exportMemo
+	projects       *projectMemo
+	workspaces     *workspaceMemo
+	subpathImports *subpathImportsMemo
+	importMaps     *importMapMemo
+	listings       *listingMemo
+	timings        *scanTimings
+	progress       *progressMeter
 }
 
 func DefaultConfig() *Config {
 	return &Config{
 		Directives:       []string{"'use client'", `"use client"`},
-		SearchExtensions: []string{".tsx", ".ts", ".jsx", ".js"},
+		SearchExtensions: []string{".tsx", ".ts", ".jsx", ".js", ".mts", ".cts", ".mjs", ".cjs"},
 		MaxReadBytes:     4096,
+		Gitignore:        true,
+		GeneratedMarkers: []string{"@generated"},
+		Limits:           defaultTraversalLimits,
+		directives:       newDirectiveMemo(),
+		exports:          newExportMemo(),
+		projects:         newProjectMemo(),
+		workspaces:       newWorkspaceMemo(),
+		subpathImports:   newSubpathImportsMemo(),
+		importMaps:       newImportMapMemo(),
+		listings:         newListingMemo(),
 	}
 }
 
 type ImportInfo struct {
 	Source     string
 	Specifiers []string
+	Names      []string
+	Namespace  string
+	Line       int
+}
+
+type Resolution struct {
+	Paths []string
+	Tried []string
+	Local bool
+}
+
+type (
+	Finding          = rscboundary.Finding
+	UnresolvedImport = rscboundary.UnresolvedImport
+	ScanResult       = rscboundary.ScanResult
+	SubtreeSize      = rscboundary.SubtreeSize
+)
+
+type clientImport struct {
+	Source   string
+	Resolved string
 }
 
+const (
+	RuleClientUsage      = "client-usage"
+	RuleUnresolvedImport = "unresolved-import"
+	RuleAmbiguousImport  = "ambiguous-import"
+	RuleClientInLoop     = "client-in-loop"
+	RuleServerAction     = "server-action"
+
+	RuleMissingClientDirective = "missing-client-directive"
+	RuleNonSerializableProp    = "non-serializable-prop"
+	RuleServerOnlyImport       = "server-only-import"
+	RuleClientOnlyImport       = "client-only-import"
+	RuleAsyncClientComponent   = "async-client-component"
+	RuleBrowserAPI             = "browser-api"
+	RuleImportCasing           = "import-casing"
+
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
 type PathAlias struct {
-	Alias  string
-	Target string
+	Pattern string
+	Alias   string
+	Target  string
+}
+
+type ProjectConfig struct {
+	Path     string
+	Extends  []string
+	Aliases  []PathAlias
+	RootDirs []string
 }
 
 type TSConfig struct {
 	CompilerOptions struct {
-		BaseURL string              `json:"baseUrl"`
-		Paths   map[string][]string `json:"paths"`
+		BaseURL  string              `json:"baseUrl"`
+		Paths    map[string][]string `json:"paths"`
+		RootDirs []string            `json:"rootDirs"`
 	} `json:"compilerOptions"`
-	Extends string `json:"extends"`
+	Extends    extendsList `json:"extends"`
+	Include    []string    `json:"include"`
+	Exclude    []string    `json:"exclude"`
+	Files      []string    `json:"files"`
+	References []struct {
+		Path string `json:"path"`
+	} `json:"references"`
+}
+
+type extendsList []string
+
+func (e *extendsList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*e = extendsList{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(e))
 }
 
 var (
 	importRegex = regexp.MustCompile(`^\s*import\s+(.+?)(?:\s+from\s+)?['"]([^'"]+)['"]`)
-	jsxTagRegex = regexp.MustCompile(`<\s*(\w+)`)
+	jsxTagRegex = regexp.MustCompile(`<\s*(` + identPattern + `)`)
 )
 
+var commands = map[string]func(args []string) error{
+	"baseline":   runBaselineCommand,
+	"cache":      runCacheCommand,
+	"daemon":     runDaemonCommand,
+	"fix":        runFixCommand,
+	"duplicates": runDuplicatesCommand,
+	"explain":    runExplainCommand,
+	"graph":      runGraphCommand,
+	"doctor":     runDoctorCommand,
+	"dynamic":    runDynamicCommand,
+	"layouts":    runLayoutsCommand,
+	"lsp":        runLSPCommand,
+	"offenders":  runOffendersCommand,
+	"packages":   runPackagesCommand,
+	"providers":  runProvidersCommand,
+	"redundant":  runRedundantCommand,
+	"routes":     runRoutesCommand,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	var (
-		path    = flag.String("path", ".", "path to scan")
-		verbose = flag.Bool("v", false, "verbose output")
+		path     = flag.String("path", ".", "path to scan")
+		verbose  = flag.Bool("v", false, "verbose output")
+		explain  = flag.Bool("explain-resolution", false, "print every candidate path tried while resolving imports")
+		trace    = flag.Bool("trace-aliases", false, "log the tsconfig and alias table used for each file")
+		strict   = flag.Bool("strict", false, "report relative and aliased imports that fail to resolve as errors")
+		format   = flag.String("format", "grep", "output format ("+strings.Join(rscboundary.Formats(), ", ")+"); pretty when stdout is a terminal")
+		context  = flag.Int("C", 0, "context lines around each finding in the pretty format")
+		color    = flag.String("color", "auto", "color the pretty format (auto, always, never)")
+		output   = flag.String("output", "", "write the report to this file instead of stdout")
+		impMap   = flag.String("import-map", "", "import map file (defaults to the nearest deno.json/deno.jsonc)")
+		project  = flag.String("project", "", "tsconfig/jsconfig to use for every file instead of the nearest one")
+		noCache  = flag.Bool("no-cache", false, "neither read nor write the on-disk cache")
+		cacheDir = flag.String("cache-dir", defaultCacheDir, "directory for the persistent cache")
+		cacheKey = flag.String("cache-key", "", "reuse complete scan results keyed by this source (git)")
+		confPath = flag.String("config", "", "config file (defaults to the nearest "+configFileName+" or rscboundary.yaml above -path)")
+		stdio    = flag.Bool("stdio-server", false, "answer boundary queries over stdin/stdout (Content-Length framed JSON)")
+		maxFind  = flag.Int("max-findings", -1, "budget for client component usages; exit 1 when exceeded (-1 for no budget)")
+		failAny  = flag.Bool("fail-on-findings", false, "exit 1 when any finding is reported, not only errors")
+		notify   = flag.String("notify-url", "", "POST a summary to this URL when the budget is exceeded")
+		notifyAs = flag.String("notify-format", "json", "notification payload format (json, slack)")
+		enable   = flag.String("enable", "", "comma-separated opt-in rules to enable ("+RuleClientInLoop+", "+RuleServerAction+", "+RuleMissingClientDirective+", "+RuleNonSerializableProp+", "+RuleBrowserAPI+", "+RuleImportCasing+")")
+		snapMode = flag.String("snapshot", "", "write or verify a snapshot of all results (write, verify)")
+		snapFile = flag.String("snapshot-file", defaultSnapshotFile, "snapshot file used by -snapshot")
+		withGen  = flag.Bool("include-generated", false, "report usages in files marked @generated")
+		flow     = flag.Bool("flow", false, "strip Flow type declarations before extracting imports")
+		follow   = flag.Bool("follow-outside-root", false, "also scan imported files that resolve outside the scanned path")
+		boundary = flag.String("outside-root-boundary", ".", "directory that -follow-outside-root never leaves")
+		stdin    = flag.Bool("stdin", false, "scan a single file read from stdin (requires -stdin-filename)")
+		stdinAs  = flag.String("stdin-filename", "", "path of the file read with -stdin, used to resolve its imports")
+		fileList = flag.Bool("stdin-filelist", false, "scan the files listed on stdin, one per line, instead of walking -path")
+		since    = flag.String("since", "", "only report findings introduced after this git revision or date")
+		changedS = flag.String("changed-since", "", "only scan files changed since this git revision and the files that depend on them")
+		baseFile = flag.String("baseline", "", "only report findings missing from this baseline file (see the baseline subcommand)")
+		jobs     = flag.Int("jobs", 0, "number of files scanned in parallel (0 for one per CPU)")
+		watch    = flag.Bool("watch", false, "rescan changed files and their importers and reprint findings on every change")
+		useGit   = flag.Bool("gitignore", true, "skip files matched by .gitignore")
+		withDeps = flag.Bool("include-node-modules", false, "resolve package imports into node_modules (exports, module, main) and check them for directives")
+		frame    = flag.String("framework", "", "apply framework conventions ("+strings.Join(frameworks, ", ")+")")
+		withMDX  = flag.Bool("mdx", false, "also scan .mdx files and resolve imports of them")
+		unsorted = flag.Bool("unsorted", false, "report findings in the order files finish scanning instead of sorting them (grep output is streamed)")
+		subtree  = flag.Bool("client-size", false, "estimate the files, lines and bytes each crossing pulls into the client bundle")
+		progress = flag.Bool("progress", false, "show how many files have been scanned on stderr")
+		timings  = flag.Bool("timings", false, "print the time spent walking, parsing, resolving, checking directives and reporting on stderr")
+		pathSep  = flag.String("path-separator", "native", "separator for file paths in reports (native, slash)")
+		maxDepth = flag.Int("max-depth", 0, "descend at most this many directory levels below the scanned path (0 for no limit)")
 	)
+	var maxSize byteSize
+	flag.Var(&maxSize, "max-file-size", "skip source files larger than this many bytes, K and M suffixes allowed (0 for no limit)")
+	var ignores stringList
+	flag.Var(&ignores, "ignore", "skip files matching this glob, relative to the scanned path (repeatable)")
+	var reports reportTargets
+	flag.Var(&reports, "report", "also write a report as format=file (repeatable, e.g. json=report.json)")
 	flag.Parse()
 
 	config := DefaultConfig()
+	config.ExplainResolution = *explain
+	config.TraceAliases = *trace
+	config.Strict = *strict
+	config.ImportMap = *impMap
+	config.Project = *project
+	config.Enable = splitList(*enable)
+	config.IncludeGenerated = *withGen
+	config.Flow = *flow
+	config.FollowOutsideRoot = *follow
+	config.OutsideBoundary = *boundary
+	config.Jobs = *jobs
+	config.Ignore = ignores
+	config.Gitignore = *useGit
+	config.IncludeNodeModules = *withDeps
+	config.Framework = *frame
+	config.MaxDepth = *maxDepth
+	config.MaxFileSize = int64(maxSize)
+	if *timings {
+		config.timings = newScanTimings()
+	}
+	if *progress {
+		config.progress = &progressMeter{}
+	}
+	if *withMDX {
+		config.ExtraExtensions = append(config.ExtraExtensions, ".mdx")
+		config.setExtensions(config.SearchExtensions)
+	}
+	if *pathSep != "native" && *pathSep != "slash" {
+		fmt.Fprintf(os.Stderr, "Error: unknown path separator: %s (want native or slash)\n", *pathSep)
+		os.Exit(1)
+	}
+	if *frame != "" && !containsString(frameworks, *frame) {
+		fmt.Fprintf(os.Stderr, "Error: unknown framework: %s\n", *frame)
+		os.Exit(1)
+	}
+
+	if !*noCache {
+		cache, err := openCache(*cacheDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring unreadable cache %s: %v\n", *cacheDir, err)
+			cache = &Cache{Dir: *cacheDir, Directives: make(map[string]DirectiveEntry), Files: make(map[string]FileEntry)}
+		}
+		cache.Stats = CacheStats{RunAt: time.Now()}
+		config.cache = cache
+	}
+
+	roots, fileConfig, err := loadScanRoots(*path, *confPath, isFlagSet(flag.CommandLine, "path"), config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if fileConfig != nil && fileConfig.Format != "" && !isFlagSet(flag.CommandLine, "format") {
+		*format = fileConfig.Format
+	} else if !isFlagSet(flag.CommandLine, "format") && *output == "" && isTerminal(os.Stdout) {
+		*format = "pretty"
+	}
+	pretty.Context = *context
+	if pretty.Color, err = colorEnabled(*color, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *output != "" && *color != "always" {
+		pretty.Color = false
+	}
+
+	if *stdio {
+		if err := runStdioServer(roots, *verbose, os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *watch {
+		if err := runWatch(roots, *format, *verbose, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var resultKey string
+	switch *cacheKey {
+	case "":
+	case "git":
+		key, err := gitTreeKey(*path, roots)
+		if err != nil && *verbose {
+			fmt.Fprintf(os.Stderr, "Warning: not caching results: %v\n", err)
+		}
+		resultKey = key
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown cache key: %s\n", *cacheKey)
+		os.Exit(1)
+	}
+
+	files := flag.Args()
+	if *fileList {
+		if *stdin {
+			fmt.Fprintln(os.Stderr, "Error: -stdin-filelist cannot be combined with -stdin")
+			os.Exit(1)
+		}
+		listed, err := readFileList(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		files = append(files, listed...)
+	}
+
+	if *changedS != "" || len(files) > 0 || *fileList || *noCache {
+		resultKey = ""
+	}
+
+	if *stdin {
+		if *stdinAs == "" {
+			fmt.Fprintln(os.Stderr, "Error: -stdin requires -stdin-filename")
+			os.Exit(1)
+		}
+		resultKey = ""
+	}
+
+	streamed := *unsorted && (*format == "grep" || *format == "pretty") && *output == "" && *since == "" && *baseFile == "" &&
+		!*subtree && !*stdin && !*fileList && len(files) == 0 && *changedS == ""
+	if streamed {
+		for _, root := range roots {
+			root.Config.stream = func(file *ScanResult) {
+				annotateRoutes(file, roots)
+				if *pathSep == "slash" {
+					slashPaths(file)
+				}
+				writeReport(os.Stdout, *format, file)
+			}
+		}
+	}
+
+	result, cached := loadCachedResult(*cacheDir, resultKey)
+	if *stdin {
+		result, err = scanStdin(os.Stdin, *stdinAs, roots, *verbose)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if len(files) > 0 || *fileList {
+		result, err = scanFileList(files, roots, *verbose)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if *changedS != "" {
+		changed, err := gitChangedFiles(*changedS)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		result, err = scanChanged(roots, changed, *verbose)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if !cached {
+		result, err = scanRoots(roots, *verbose)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if resultKey != "" {
+			if err := storeCachedResult(*cacheDir, resultKey, result); err != nil && *verbose {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache results: %v\n", err)
+			}
+		}
+	} else if *verbose {
+		fmt.Fprintf(os.Stderr, "Using cached results %s\n", resultKey)
+	}
+
+	if !*unsorted {
+		sortFindings(result.Findings)
+	}
+
+	if *subtree {
+		annotateClientSubtrees(result, roots, *verbose)
+	}
+	annotateRoutes(result, roots)
+
+	if *since != "" {
+		if err := filterSince(result, *since, roots); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	baselined := 0
+	if *baseFile != "" {
+		baseline, err := readBaseline(*baseFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		baselined = baseline.filter(result)
+	}
+
+	if *pathSep == "slash" {
+		slashPaths(result)
+	}
+
+	if config.cache != nil {
+		if err := config.cache.save(); err != nil && *verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write cache: %v\n", err)
+		}
+	}
+
+	snapshotMismatch := false
+	switch *snapMode {
+	case "":
+	case "write":
+		if err := writeSnapshot(*snapFile, newSnapshot(result)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "verify":
+		expected, err := readSnapshot(*snapFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !verifySnapshot(os.Stderr, expected, newSnapshot(result)) {
+			fmt.Fprintf(os.Stderr, "results differ from snapshot %s\n", *snapFile)
+			snapshotMismatch = true
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown snapshot mode: %s\n", *snapMode)
+		os.Exit(1)
+	}
+
+	config.progress.finish()
+	reportStart := time.Now()
+	switch {
+	case *output != "":
+		err = writeReportFile(reportTarget{Format: *format, Path: *output}, result)
+	case !streamed || cached:
+		err = writeReport(os.Stdout, *format, result)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, target := range reports {
+		if err := writeReportFile(target, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	config.timings.add(phaseReport, reportStart)
+	if config.timings != nil {
+		jobs := config.Jobs
+		if jobs < 1 {
+			jobs = runtime.NumCPU()
+		}
+		config.timings.write(os.Stderr, jobs)
+	}
+
+	if n := result.SuppressedCount(); n > 0 && *format == "grep" {
+		fmt.Fprintf(os.Stderr, "%d findings suppressed by rsc-boundary comments\n", n)
+	}
+
+	if baselined > 0 && *format == "grep" {
+		fmt.Fprintf(os.Stderr, "%d findings hidden by baseline %s\n", baselined, *baseFile)
+	}
+
+	if *verbose && len(result.Unresolved) > 0 {
+		fmt.Fprintf(os.Stderr, "%d local imports could not be resolved\n", len(result.Unresolved))
+	}
 
-	if err := scanPath(*path, config, *verbose); err != nil {
+	breaches, err := checkPathBudgets(result, fileConfig)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if breach := checkBudget(result, *maxFind, *path); breach != nil {
+		breaches = append([]*BudgetSummary{breach}, breaches...)
+	}
+	for _, breach := range breaches {
+		fmt.Fprintln(os.Stderr, breach)
+		if *notify != "" {
+			if err := sendNotification(*notify, *notifyAs, breach); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: notification failed: %v\n", err)
+			}
+		}
+	}
+
+	if hasErrors(result.Findings) || snapshotMismatch || len(breaches) > 0 || (*failAny && len(result.Findings) > 0) {
+		os.Exit(1)
+	}
 }
 
-func scanPath(root string, config *Config, verbose bool) error {
-	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (c *Config) ruleEnabled(rule string) bool {
+	if severity, ok := c.Severities[rule]; ok {
+		return severity != SeverityOff
+	}
+	for _, enabled := range c.Enable {
+		if enabled == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func hasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func scanRoots(roots []ScanRoot, verbose bool) (*ScanResult, error) {
+	result := &ScanResult{}
+
+	for _, root := range roots {
+		if err := scanPath(root.Path, root.Config, verbose, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func scanStdin(r io.Reader, filePath string, roots []ScanRoot, verbose bool) (*ScanResult, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScanResult{}
+	if err := scanContent(filePath, content, rootConfig(roots, filePath), verbose, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func readFileList(r io.Reader) ([]string, error) {
+	var files []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, scanner.Err()
+}
+
+func scanFileList(files []string, roots []ScanRoot, verbose bool) (*ScanResult, error) {
+	result := &ScanResult{}
+
+	for _, path := range files {
+		config := rootConfig(roots, path)
+		info, err := os.Stat(path)
+		if err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", path, err)
+			}
+			continue
+		}
+
+		if info.IsDir() {
+			if err := scanPath(path, config, verbose, result); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !isSupportedFile(path, config.SearchExtensions) {
+			continue
+		}
+		if err := scanFile(path, config, verbose, result); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to scan %s: %v\n", path, err)
+		}
+	}
+
+	result.Resolved = nil
+	return result, nil
+}
+
+func rootConfig(roots []ScanRoot, filePath string) *Config {
+	for _, root := range roots {
+		if isWithin(absPath(filePath), absPath(root.Path)) {
+			return root.Config
+		}
+	}
+	return roots[0].Config
+}
+
+func scanPath(root string, config *Config, verbose bool, result *ScanResult) error {
+	scan := func(path string, result *ScanResult) {
+		if err := scanFile(path, config, verbose, result); err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Warning: failed to scan %s: %v\n", path, err)
+			}
+		}
+	}
+
+	var files []string
+	walkStart := time.Now()
+	if err := walkSourceFiles(root, config, func(path string) {
+		files = append(files, path)
+	}); err != nil {
+		return err
+	}
+	config.timings.add(phaseWalk, walkStart)
+	config.progress.expect(len(files))
+
+	jobs := config.Jobs
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	if config.ExplainResolution || config.TraceAliases {
+		jobs = 1
+	}
+
+	var streamMu sync.Mutex
+	stream := func(file *ScanResult) {
+		if config.stream != nil {
+			streamMu.Lock()
+			config.stream(file)
+			streamMu.Unlock()
+		}
+	}
+
+	results := make([]ScanResult, len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				scan(files[index], &results[index])
+				config.progress.step()
+				stream(&results[index])
+			}
+		}()
+	}
+	for index := range files {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	result.Resolved = nil
+	for i := range results {
+		result.Merge(&results[i])
+	}
+	if !config.FollowOutsideRoot {
+		result.Resolved = nil
+		return nil
+	}
+
+	absRoot := canonicalPath(root)
+	boundary := canonicalPath(config.OutsideBoundary)
+	followed := make(map[string]bool)
+	for _, file := range files {
+		followed[canonicalPath(file)] = true
+	}
+	for len(result.Resolved) > 0 {
+		path := result.Resolved[0]
+		result.Resolved = result.Resolved[1:]
+
+		abs := canonicalPath(path)
+		if followed[abs] || isWithin(abs, absRoot) || !isWithin(abs, boundary) ||
+			!isSupportedFile(abs, config.SearchExtensions) || strings.Contains(abs, string(filepath.Separator)+"node_modules"+string(filepath.Separator)) {
+			continue
+		}
+		followed[abs] = true
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Following %s outside %s\n", path, root)
+		}
+		followedResult := &ScanResult{}
+		config.progress.expect(1)
+		scan(path, followedResult)
+		config.progress.step()
+		stream(followedResult)
+		result.Merge(followedResult)
+	}
+	result.Resolved = nil
+
+	return nil
+}
+
+func walkSourceFiles(root string, config *Config, fn func(path string)) error {
+	var ignore *gitignore
+	if config.Gitignore {
+		ignore = newGitignore(root)
+	}
+
+	return walkTree(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
+		if isIgnored(root, path, config.Ignore) || frameworkExcluded(root, path, config) || (ignore != nil && path != root && ignore.ignored(path, info.IsDir())) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		if info.IsDir() {
 			name := info.Name()
 			if name == "node_modules" || name == ".git" || name == "dist" || name == "build" {
 				return filepath.SkipDir
 			}
+			if config.MaxDepth > 0 && path != root && directoryDepth(root, path) > config.MaxDepth {
+				return filepath.SkipDir
+			}
+			if ignore != nil {
+				ignore.load(path)
+			}
 			return nil
 		}
 
@@ -82,16 +812,30 @@ func scanPath(root string, config *Config, verbose bool) error {
 			return nil
 		}
 
-		if err := scanFile(path, config, verbose); err != nil {
-			if verbose {
-				fmt.Fprintf(os.Stderr, "Warning: failed to scan %s: %v\n", path, err)
-			}
-		}
-
+		fn(path)
 		return nil
 	})
 }
 
+func isIgnored(root, path string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range patterns {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
 func isSupportedFile(path string, extensions []string) bool {
 	ext := filepath.Ext(path)
 	for _, e := range extensions {
@@ -102,90 +846,359 @@ func isSupportedFile(path string, extensions []string) bool {
 	return false
 }
 
-func scanFile(filePath string, config *Config, verbose bool) error {
+func scanFile(filePath string, config *Config, verbose bool, result *ScanResult) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
+	if info, err := file.Stat(); err == nil && config.oversized(filePath, info.Size(), verbose) {
+		return nil
+	}
+
+	readStart := time.Now()
 	content, err := io.ReadAll(file)
 	if err != nil {
 		return err
 	}
+	config.timings.add(phaseParse, readStart)
+	if config.timings != nil {
+		atomic.AddInt64(&config.timings.files, 1)
+	}
+	result.Scanned = append(result.Scanned, filePath)
+
+	if config.cache != nil && !config.ExplainResolution && !config.TraceAliases {
+		return config.cache.scanContent(filePath, content, config, verbose, result)
+	}
+	return scanContent(filePath, content, config, verbose, result)
+}
+
+func scanContent(filePath string, content []byte, config *Config, verbose bool, result *ScanResult) error {
+	if !config.IncludeGenerated && isGeneratedFile(content, config) {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Skipping generated file %s\n", filePath)
+		}
+		return nil
+	}
+
+	if rules, ok := fileSuppression(content, config); ok {
+		defer suppressFindings(result, len(result.Findings), rules)
+	}
 
-	lines := strings.Split(string(content), "\n")
+	lines := splitLines(string(content))
+	if suppressions := lineSuppressions(lines); len(suppressions) > 0 {
+		defer suppressLines(result, len(result.Findings), suppressions)
+	}
+	if config.Flow {
+		lines = stripFlowTypes(lines)
+	}
+
+	defer fingerprintFindings(result, len(result.Findings))
+	defer applySeverities(result, len(result.Findings), config.Severities)
+
+	if config.ruleEnabled(RuleMissingClientDirective) && !hasDirective(bytes.NewReader(content), config) && !hasServerDirective(content, config) {
+		result.Findings = append(result.Findings, findMissingDirective(filePath, lines)...)
+	}
+	if config.ruleEnabled(RuleBrowserAPI) && !hasDirective(bytes.NewReader(content), config) {
+		result.Findings = append(result.Findings, findBrowserAPIs(filePath, lines)...)
+	}
 
-	imports := parseImports(lines)
+	parseStart := time.Now()
+	imports := append(parseImports(lines), parseLazyImports(lines)...)
+	config.timings.add(phaseParse, parseStart)
 	if len(imports) == 0 {
 		return nil
 	}
 
 	baseDir := filepath.Dir(filePath)
-	aliases, err := loadPathAliases(baseDir)
+	project, err := loadProjectConfig(filePath, config)
 	if err != nil && verbose {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load aliases for %s: %v\n", filePath, err)
 	}
+	traceAliasTable(config, filePath, project)
+
+	importMap, err := loadImportMap(baseDir, config)
+	if err != nil && verbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load import map for %s: %v\n", filePath, err)
+	}
+	if importMap != nil {
+		config.dependsOn(importMap.Path)
+	}
+
+	clientComponents := make(map[string]clientImport)
+	serverActions := make(map[string]serverActionImport)
+
+	resolutions := make([]Resolution, len(imports))
+	for index, imp := range imports {
+		explainf(config, "%s: import '%s'", filePath, imp.Source)
+		resolveStart := time.Now()
+		resolution := resolveImportPath(baseDir, imp.Source, project, importMap, config)
+		config.timings.add(phaseResolve, resolveStart)
+		resolutions[index] = resolution
+		if len(resolution.Paths) == 0 && resolution.Local {
+			explainf(config, "  unresolved")
+			result.Unresolved = append(result.Unresolved, UnresolvedImport{
+				File:   filePath,
+				Line:   imp.Line,
+				Source: imp.Source,
+				Tried:  resolution.Tried,
+			})
+			if config.Strict || config.ruleEnabled(RuleUnresolvedImport) {
+				result.Findings = append(result.Findings, Finding{
+					File:         filePath,
+					Line:         imp.Line,
+					Content:      lines[imp.Line-1],
+					Rule:         RuleUnresolvedImport,
+					Severity:     SeverityError,
+					Message:      fmt.Sprintf("unresolved import '%s' (tried: %s)", imp.Source, strings.Join(resolution.Tried, ", ")),
+					ImportSource: imp.Source,
+				})
+			}
+		}
+
+		result.Resolved = append(result.Resolved, resolution.Paths...)
+
+		clientCount := 0
+		clientPath := ""
+		var statuses []string
+		for _, resolvedPath := range resolution.Paths {
+			if isClientFile(resolvedPath, config) {
+				explainf(config, "  %s: client (directive found)", resolvedPath)
+				clientCount++
+				if clientPath == "" {
+					clientPath = resolvedPath
+				}
+				statuses = append(statuses, resolvedPath+" (client)")
+				continue
+			}
+			explainf(config, "  %s: not client (no directive)", resolvedPath)
+			statuses = append(statuses, resolvedPath+" (server)")
+		}
+
+		if imp.Namespace != "" && len(resolution.Paths) > 0 {
+			for _, member := range namespaceMembers(content, imp.Namespace) {
+				defining := resolveReexport(resolution.Paths[0], member, config)
+				if defining != resolution.Paths[0] {
+					result.Resolved = append(result.Resolved, defining)
+				}
+				if isClientFile(defining, config) {
+					clientComponents[imp.Namespace+"."+member] = clientImport{Source: imp.Source, Resolved: defining}
+				}
+			}
+		}
 
-	clientComponents := make(map[string]bool)
+		if external, ok := externalSpecifier(baseDir, imp.Source, config); !resolution.Local && ok && config.isClientPackage(packageName(external)) {
+			explainf(config, "  %s: client (listed in clientPackages)", packageName(external))
+			for _, spec := range imp.Specifiers {
+				clientComponents[spec] = clientImport{Source: imp.Source}
+			}
+			if imp.Namespace != "" {
+				for _, member := range namespaceMembers(content, imp.Namespace) {
+					clientComponents[imp.Namespace+"."+member] = clientImport{Source: imp.Source}
+				}
+			}
+		}
 
-	for _, imp := range imports {
-		resolvedPaths := resolveImportPath(baseDir, imp.Source, aliases, config)
+		if config.ruleEnabled(RuleServerAction) {
+			for _, resolvedPath := range resolution.Paths {
+				if isServerActionFile(resolvedPath, config) {
+					for _, spec := range imp.Specifiers {
+						serverActions[spec] = serverActionImport{Source: imp.Source, Resolved: resolvedPath, Line: imp.Line}
+					}
+					break
+				}
+			}
+		}
 
-		for _, resolvedPath := range resolvedPaths {
-			if fileHasDirective(resolvedPath, config) {
-				for _, spec := range imp.Specifiers {
-					clientComponents[spec] = true
+		if clientCount > 0 {
+			for _, spec := range imp.Specifiers {
+				clientComponents[spec] = clientImport{Source: imp.Source, Resolved: clientPath}
+			}
+			if clientCount < len(statuses) {
+				result.Findings = append(result.Findings, Finding{
+					File:         filePath,
+					Line:         imp.Line,
+					Content:      lines[imp.Line-1],
+					Rule:         RuleAmbiguousImport,
+					Severity:     SeverityWarning,
+					Message:      fmt.Sprintf("ambiguous import '%s' resolves to files with different directives: %s", imp.Source, strings.Join(statuses, ", ")),
+					ImportSource: imp.Source,
+				})
+			}
+		} else if len(resolution.Paths) > 0 {
+			for i, spec := range imp.Specifiers {
+				if i >= len(imp.Names) {
+					break
+				}
+				defining := resolveReexport(resolution.Paths[0], imp.Names[i], config)
+				if defining == resolution.Paths[0] {
+					continue
+				}
+				result.Resolved = append(result.Resolved, defining)
+				if isClientFile(defining, config) {
+					explainf(config, "  %s: client (directive found)", defining)
+					clientComponents[spec] = clientImport{Source: imp.Source, Resolved: defining}
 				}
-				break
 			}
 		}
 	}
 
+	isClient := hasDirective(bytes.NewReader(content), config)
+	isServer := !isClient && (hasServerDirective(content, config) || (config.Framework == frameworkNextJS && isRouteEntry(filePath)))
+	result.Findings = append(result.Findings, findPoisonedImports(filePath, lines, imports, resolutions, isClient, isServer, config)...)
+	if isClient {
+		result.Findings = append(result.Findings, findAsyncComponents(filePath, lines, imports, resolutions, config)...)
+	}
+	if config.ruleEnabled(RuleImportCasing) {
+		result.Findings = append(result.Findings, findCasingMismatches(filePath, lines, imports, resolutions, config)...)
+	}
+	if len(config.Boundaries) > 0 {
+		result.Findings = append(result.Findings, findBoundaryCrossings(filePath, content, lines, imports, resolutions, config)...)
+	}
+	if config.ruleEnabled(RuleServerAction) {
+		result.Findings = append(result.Findings, findServerActions(filePath, string(content), lines, serverActions, clientComponents, isClient)...)
+	}
+
 	if len(clientComponents) == 0 {
 		return nil
 	}
 
+	components := make([]string, 0, len(clientComponents))
+	for component := range clientComponents {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	tagRegexes := make([]*regexp.Regexp, len(components))
+	propRegexes := make([]*regexp.Regexp, len(components))
+	for i, component := range components {
+		tagRegexes[i] = regexp.MustCompile(jsxTagPattern(component))
+		propRegexes[i] = regexp.MustCompile(jsxPropReferencePattern(component))
+	}
+
+	type usage struct {
+		column    int
+		endColumn int
+		component string
+	}
+	text := strings.Join(lines, "\n")
+	references := make(map[int][]usage)
+	for _, ref := range findComponentReferences(text, clientComponents) {
+		references[ref.line] = append(references[ref.line], usage{ref.column, ref.endColumn, ref.component})
+	}
+	code := codeMask(text, tokenize(text))
+	lineStart := 0
 	for lineNum, line := range lines {
-		for component := range clientComponents {
-			if containsJSXTag(line, component) {
-				fmt.Printf("%s:%d:%s\n", filePath, lineNum+1, line)
-				break
+		usages := references[lineNum+1]
+		for i, component := range components {
+			for _, loc := range tagRegexes[i].FindAllStringIndex(line, -1) {
+				if code[lineStart+loc[0]] {
+					end := loc[0] + strings.Index(line[loc[0]:], component) + len(component)
+					usages = append(usages, usage{loc[0] + 1, end + 1, component})
+				}
+			}
+			for _, loc := range propRegexes[i].FindAllStringSubmatchIndex(line, -1) {
+				if code[lineStart+loc[2]] {
+					usages = append(usages, usage{loc[2] + 1, loc[3] + 1, component})
+				}
 			}
 		}
+		lineStart += len(line) + 1
+		sort.Slice(usages, func(i, j int) bool {
+			return usages[i].column < usages[j].column
+		})
+
+		for _, u := range usages {
+			result.Findings = append(result.Findings, Finding{
+				File:               filePath,
+				Line:               lineNum + 1,
+				Column:             u.column,
+				EndColumn:          u.endColumn,
+				Content:            line,
+				Rule:               RuleClientUsage,
+				Severity:           SeverityWarning,
+				Component:          u.component,
+				ImportSource:       clientComponents[u.component].Source,
+				ResolvedClientFile: clientComponents[u.component].Resolved,
+				InClientFile:       isClient,
+			})
+		}
+	}
+
+	if config.ruleEnabled(RuleClientInLoop) && !isClient {
+		result.Findings = append(result.Findings, findLoopRenders(filePath, string(content), lines, clientComponents)...)
+	}
+	if config.ruleEnabled(RuleNonSerializableProp) && !isClient {
+		result.Findings = append(result.Findings, findNonSerializableProps(filePath, strings.Join(lines, "\n"), lines, clientComponents)...)
 	}
 
 	return nil
 }
 
+func splitLines(content string) []string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
 func parseImports(lines []string) []ImportInfo {
 	var imports []ImportInfo
-	var currentImport string
 
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
+	tokens := tokenize(strings.Join(lines, "\n"))
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].kind != tokenIdent || tokens[i].text != "import" || !statementStart(tokens, i) {
+			continue
+		}
 
-		if currentImport != "" {
-			currentImport += " " + trimmed
-		} else if strings.HasPrefix(trimmed, "import ") {
-			currentImport = trimmed
+		end, ok := importDeclarationEnd(tokens, i+1)
+		if !ok {
+			continue
 		}
 
-		if currentImport != "" {
-			if strings.Contains(currentImport, `"`) || strings.Contains(currentImport, `'`) {
-				if imp := parseImportStatement(currentImport); imp != nil {
-					imports = append(imports, *imp)
-				}
-				currentImport = ""
-			}
+		parts := make([]string, 0, end-i)
+		for _, t := range tokens[i:end] {
+			parts = append(parts, t.text)
+		}
+		if imp := parseImportStatement(strings.Join(parts, " ")); imp != nil {
+			imp.Line = tokens[i].line
+			imports = append(imports, *imp)
 		}
+		i = end - 1
 	}
 
 	return imports
 }
 
+func statementStart(tokens []token, i int) bool {
+	return tokens[i].lineStart || (i > 0 && tokens[i-1].kind == tokenPunct && tokens[i-1].text == ";")
+}
+
+func importDeclarationEnd(tokens []token, start int) (int, bool) {
+	for i := start; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.kind == tokenString:
+			if i == start || (i > start && tokens[i-1].kind == tokenIdent && tokens[i-1].text == "from") {
+				return i + 1, true
+			}
+			if i+1 < len(tokens) && tokens[i+1].text == "as" {
+				continue
+			}
+			return 0, false
+		case t.kind == tokenIdent:
+		case t.kind == tokenPunct && (t.text == "{" || t.text == "}" || t.text == "," || t.text == "*"):
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
 func parseImportStatement(stmt string) *ImportInfo {
-	if regexp.MustCompile(`^\s*import\s+type\s`).MatchString(stmt) {
+	if regexp.MustCompile(`^\s*import\s+type(?:of)?\s`).MatchString(stmt) {
 		return nil
 	}
 
@@ -218,24 +1231,26 @@ func parseImportStatement(stmt string) *ImportInfo {
 		return &ImportInfo{Source: source, Specifiers: specifiers}
 	}
 
-	if match := regexp.MustCompile(`^([\w$]+)\s*,\s*\{(.*)\}$`).FindStringSubmatch(clauseText); match != nil {
-		specifiers = append(specifiers, strings.TrimSpace(match[1]))
-		specifiers = append(specifiers, parseNamedSpecifiers(match[2])...)
-		return &ImportInfo{Source: source, Specifiers: specifiers}
+	var names []string
+	if head, tail, ok := strings.Cut(clauseText, ","); ok && !strings.ContainsAny(head, "{*") {
+		specifiers = append(specifiers, strings.TrimSpace(head))
+		names = append(names, "default")
+		clauseText = strings.TrimSpace(tail)
 	}
 
 	if match := regexp.MustCompile(`^\{(.*)\}$`).FindStringSubmatch(clauseText); match != nil {
 		specifiers = append(specifiers, parseNamedSpecifiers(match[1])...)
-		return &ImportInfo{Source: source, Specifiers: specifiers}
+		return &ImportInfo{Source: source, Specifiers: specifiers, Names: append(names, parseImportedNames(match[1])...)}
 	}
 
-	if regexp.MustCompile(`^\*\s+as\s+[\w$]+$`).MatchString(clauseText) {
-		return &ImportInfo{Source: source, Specifiers: specifiers}
+	if match := regexp.MustCompile(`^\*\s*as\s+(` + identPattern + `)$`).FindStringSubmatch(clauseText); match != nil {
+		return &ImportInfo{Source: source, Specifiers: specifiers, Names: append(names, "*"), Namespace: match[1]}
 	}
 
-	specifiers = append(specifiers, clauseText)
-
-	return &ImportInfo{Source: source, Specifiers: specifiers}
+	if len(names) > 0 {
+		return &ImportInfo{Source: source, Specifiers: specifiers, Names: names}
+	}
+	return &ImportInfo{Source: source, Specifiers: []string{clauseText}, Names: []string{"default"}}
 }
 
 func parseNamedSpecifiers(body string) []string {
@@ -243,11 +1258,11 @@ func parseNamedSpecifiers(body string) []string {
 
 	for _, chunk := range strings.Split(body, ",") {
 		trimmed := strings.TrimSpace(chunk)
-		if trimmed == "" || strings.HasPrefix(trimmed, "type ") {
+		if trimmed == "" || strings.HasPrefix(trimmed, "type ") || strings.HasPrefix(trimmed, "typeof ") {
 			continue
 		}
 
-		if match := regexp.MustCompile(`^.*\s+as\s+([\w$]+)$`).FindStringSubmatch(trimmed); match != nil {
+		if match := regexp.MustCompile(`^.*\s+as\s+(` + identPattern + `)$`).FindStringSubmatch(trimmed); match != nil {
 			specifiers = append(specifiers, match[1])
 		} else {
 			specifiers = append(specifiers, trimmed)
@@ -257,53 +1272,156 @@ func parseNamedSpecifiers(body string) []string {
 	return specifiers
 }
 
-func resolveImportPath(baseDir, importPath string, aliases []PathAlias, config *Config) []string {
-	var candidates []string
+func parseImportedNames(body string) []string {
+	var names []string
+
+	for _, chunk := range strings.Split(body, ",") {
+		trimmed := strings.TrimSpace(chunk)
+		if trimmed == "" || strings.HasPrefix(trimmed, "type ") || strings.HasPrefix(trimmed, "typeof ") {
+			continue
+		}
+
+		if idx := strings.Index(trimmed, " as "); idx >= 0 {
+			trimmed = strings.TrimSpace(trimmed[:idx])
+		}
+		names = append(names, strings.Trim(trimmed, `'"`))
+	}
+
+	return names
+}
+
+func resolveImportPath(baseDir, importPath string, project *ProjectConfig, importMap *ImportMap, config *Config) Resolution {
+	var resolution Resolution
 
 	if strings.HasPrefix(importPath, ".") {
 		basePath := filepath.Join(baseDir, importPath)
-		candidates = append(candidates, expandPath(basePath, config)...)
-		return candidates
+		explainf(config, "  relative: %s", basePath)
+		resolution.Local = true
+		resolution.expand(basePath, config)
+		if len(resolution.Paths) == 0 {
+			for _, candidate := range project.rootDirCandidates(basePath) {
+				explainf(config, "  rootDirs: %s", candidate)
+				resolution.expand(candidate, config)
+			}
+		}
+		return resolution
+	}
+
+	if strings.HasPrefix(importPath, "#") {
+		if resolution, ok := resolveSubpathImport(baseDir, importPath, config); ok {
+			return resolution
+		}
 	}
 
-	for _, alias := range aliases {
-		if strings.HasPrefix(importPath, alias.Alias) {
+	if target, ok := importMap.Resolve(baseDir, importPath); ok {
+		explainf(config, "  import map %s -> %s", importMap.Path, target)
+		resolution.Local = true
+		resolution.expand(target, config)
+		return resolution
+	}
+
+	for _, alias := range project.Aliases {
+		if alias.matches(importPath) {
 			remainder := strings.TrimPrefix(importPath, alias.Alias)
 			remainder = strings.TrimPrefix(remainder, "/")
 
 			targetPath := filepath.Join(alias.Target, remainder)
-			candidates = append(candidates, expandPath(targetPath, config)...)
+			explainf(config, "  alias '%s' -> %s", alias.Alias, targetPath)
+			tracef(config, "'%s' matched pattern '%s' -> %s", importPath, alias.Pattern, targetPath)
+			resolution.Local = true
+			resolution.expand(targetPath, config)
 		}
 	}
 
-	return candidates
+	if !resolution.Local && config.workspaces != nil && isPackageSpecifier(importPath) {
+		config.dependsOnProbes(absPath(baseDir), "", "pnpm-workspace.yaml", "package.json")
+		if dir, ok := config.workspaces.lookup(baseDir, packageName(importPath)); ok {
+			explainf(config, "  workspace package '%s' -> %s", packageName(importPath), dir)
+			resolution.Local = true
+			resolution.Paths = resolveWorkspacePackage(dir, importPath, config)
+			resolution.Tried = append(resolution.Tried, dir)
+			return resolution
+		}
+	}
+
+	if !resolution.Local && config.IncludeNodeModules && isPackageSpecifier(importPath) {
+		explainf(config, "  package '%s'", packageName(importPath))
+		resolution.Paths = resolveNodeModule(baseDir, importPath, config)
+	} else if !resolution.Local {
+		explainf(config, "  no alias matches (bare specifier, skipped)")
+	}
+
+	return resolution
+}
+
+func (a PathAlias) matches(importPath string) bool {
+	switch {
+	case strings.HasSuffix(a.Pattern, "/*"):
+		return importPath == a.Alias || strings.HasPrefix(importPath, a.Alias+"/")
+	case strings.HasSuffix(a.Pattern, "*"):
+		return strings.HasPrefix(importPath, a.Alias)
+	default:
+		return importPath == a.Alias
+	}
+}
+
+func (r *Resolution) expand(basePath string, config *Config) {
+	paths, tried := expandPath(basePath, config)
+	r.Paths = append(r.Paths, paths...)
+	r.Tried = append(r.Tried, tried...)
 }
 
-func expandPath(basePath string, config *Config) []string {
+func expandPath(basePath string, config *Config) ([]string, []string) {
 	var paths []string
+	tried := []string{basePath}
+	config.dependsOn(basePath)
+	config.dependsOn(filepath.Dir(basePath))
 
 	if fileExists(basePath) {
+		explainf(config, "    %s: accepted (exact file)", basePath)
 		paths = append(paths, basePath)
-		return paths
+		return paths, tried
+	}
+	explainf(config, "    %s: rejected (%s)", basePath, missReason(basePath))
+
+	ext := filepath.Ext(basePath)
+	for _, source := range typeScriptSources[ext] {
+		sourcePath := strings.TrimSuffix(basePath, ext) + source
+		tried = append(tried, sourcePath)
+		if fileExists(sourcePath) {
+			explainf(config, "    %s: accepted (TypeScript source for %s)", sourcePath, ext)
+			return append(paths, sourcePath), tried
+		}
+		explainf(config, "    %s: rejected (%s)", sourcePath, missReason(sourcePath))
 	}
 
 	for _, ext := range config.SearchExtensions {
 		pathWithExt := basePath + ext
+		tried = append(tried, pathWithExt)
 		if fileExists(pathWithExt) {
+			explainf(config, "    %s: accepted (extension %s)", pathWithExt, ext)
 			paths = append(paths, pathWithExt)
+		} else {
+			explainf(config, "    %s: rejected (%s)", pathWithExt, missReason(pathWithExt))
 		}
 	}
 
 	if info, err := os.Stat(basePath); err == nil && info.IsDir() {
 		for _, ext := range config.SearchExtensions {
 			indexPath := filepath.Join(basePath, "index"+ext)
+			tried = append(tried, indexPath)
 			if fileExists(indexPath) {
+				explainf(config, "    %s: accepted (directory index)", indexPath)
 				paths = append(paths, indexPath)
+			} else {
+				explainf(config, "    %s: rejected (%s)", indexPath, missReason(indexPath))
 			}
 		}
+	} else {
+		explainf(config, "    %s/index.*: skipped (not a directory)", basePath)
 	}
 
-	return paths
+	return paths, tried
 }
 
 func fileExists(path string) bool {
@@ -311,6 +1429,52 @@ func fileExists(path string) bool {
 	return err == nil && !info.IsDir()
 }
 
+func missReason(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "not found"
+	}
+	if info.IsDir() {
+		return "is a directory"
+	}
+	return "unknown"
+}
+
+func explainf(config *Config, format string, args ...interface{}) {
+	if !config.ExplainResolution {
+		return
+	}
+	w := config.explainOutput
+	if w == nil {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, "explain: "+format+"\n", args...)
+}
+
+func tracef(config *Config, format string, args ...interface{}) {
+	if !config.TraceAliases {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "alias: "+format+"\n", args...)
+}
+
+func traceAliasTable(config *Config, filePath string, project *ProjectConfig) {
+	if !config.TraceAliases {
+		return
+	}
+	if project.Path == "" {
+		tracef(config, "%s: no tsconfig/jsconfig found", filePath)
+		return
+	}
+	tracef(config, "%s: using %s", filePath, project.Path)
+	for _, parent := range project.Extends {
+		tracef(config, "  extends %s", parent)
+	}
+	for _, alias := range project.Aliases {
+		tracef(config, "  %s -> %s", alias.Pattern, alias.Target)
+	}
+}
+
 func fileHasDirective(filePath string, config *Config) bool {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -318,132 +1482,314 @@ func fileHasDirective(filePath string, config *Config) bool {
 	}
 	defer file.Close()
 
-	limitedReader := io.LimitReader(file, config.MaxReadBytes)
-	scanner := bufio.NewScanner(limitedReader)
+	return hasDirective(file, config)
+}
 
-	inBlockComment := false
+func containsJSXTag(line, componentName string) bool {
+	return jsxTagColumn(line, componentName) > 0
+}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+func jsxTagColumn(line, componentName string) int {
+	loc := regexp.MustCompile(jsxTagPattern(componentName)).FindStringIndex(line)
+	if loc == nil {
+		return 0
+	}
+	return loc[0] + 1
+}
 
-		if line == "" {
-			continue
-		}
+func loadProjectConfig(filePath string, config *Config) (*ProjectConfig, error) {
+	configPath := config.Project
+	if configPath == "" {
+		configPath = config.projects.configPath(filePath)
+		config.dependsOnProjectProbes(filePath, configPath)
+	}
+	if configPath == "" {
+		return &ProjectConfig{Aliases: config.Aliases}, nil
+	}
+	configPath = config.projects.referenced(configPath, filePath)
 
-		if inBlockComment {
-			if strings.Contains(line, "*/") {
-				inBlockComment = false
-			}
-			continue
-		}
+	project, err := config.projects.parse(configPath)
+	config.dependsOn(configPath)
+	if err != nil {
+		return &ProjectConfig{Aliases: config.Aliases}, err
+	}
+	for _, parent := range project.Extends {
+		config.dependsOn(parent)
+	}
+	if len(config.Aliases) > 0 {
+		withAliases := *project
+		withAliases.Aliases = append(append([]PathAlias{}, config.Aliases...), project.Aliases...)
+		project = &withAliases
+	}
+	return project, nil
+}
 
-		for _, directive := range config.Directives {
-			trimmedLine := strings.TrimSuffix(line, ";")
-			if trimmedLine == directive {
-				return true
-			}
-		}
+func findProjectConfig(filePath string) string {
+	baseDir := filepath.Dir(filePath)
 
-		if strings.HasPrefix(line, "//") {
-			continue
+	if isTypeScriptFile(filePath) {
+		if configPath := findUp(baseDir, "tsconfig.json", "tsconfig.base.json"); configPath != "" {
+			return configPath
 		}
+		return findUp(baseDir, "jsconfig.json")
+	}
 
-		if strings.HasPrefix(line, "/*") {
-			if !strings.Contains(line, "*/") {
-				inBlockComment = true
-			}
-			continue
-		}
+	return findUp(baseDir, "jsconfig.json", "tsconfig.json", "tsconfig.base.json")
+}
 
-		if !strings.HasPrefix(line, "//") && !strings.HasPrefix(line, "/*") {
-			break
-		}
+// dependsOnProjectProbes records the tsconfig/jsconfig locations
+// findProjectConfig checked before it settled on found.
+func (c *Config) dependsOnProjectProbes(filePath, found string) {
+	baseDir := filepath.Dir(filePath)
+	if !isTypeScriptFile(filePath) {
+		c.dependsOnProbes(baseDir, found, "jsconfig.json", "tsconfig.json", "tsconfig.base.json")
+		return
+	}
+	c.dependsOnProbes(baseDir, found, "tsconfig.json", "tsconfig.base.json")
+	if found == "" || filepath.Base(found) == "jsconfig.json" {
+		c.dependsOnProbes(baseDir, found, "jsconfig.json")
 	}
-
-	return false
 }
 
-func containsJSXTag(line, componentName string) bool {
-	pattern := `<\s*` + regexp.QuoteMeta(componentName) + `\b`
-	matched, _ := regexp.MatchString(pattern, line)
-	return matched
+var typeScriptSources = map[string][]string{
+	".js":  {".ts", ".tsx"},
+	".jsx": {".tsx"},
+	".mjs": {".mts"},
+	".cjs": {".cts"},
 }
 
-func loadPathAliases(baseDir string) ([]PathAlias, error) {
-	configPaths := []string{
-		"tsconfig.json",
-		"jsconfig.json",
-		"tsconfig.base.json",
+func isTypeScriptFile(filePath string) bool {
+	switch filepath.Ext(filePath) {
+	case ".ts", ".tsx", ".mts", ".cts":
+		return true
 	}
+	return false
+}
 
-	currentDir := baseDir
+func findUp(dir string, names ...string) string {
+	currentDir := dir
 	for {
-		for _, configFile := range configPaths {
-			configPath := filepath.Join(currentDir, configFile)
-			if fileExists(configPath) {
-				return parseAliases(configPath)
+		for _, name := range names {
+			candidate := filepath.Join(currentDir, name)
+			if fileExists(candidate) {
+				return candidate
 			}
 		}
 
 		parent := filepath.Dir(currentDir)
 		if parent == currentDir {
-			break
+			return ""
 		}
 		currentDir = parent
 	}
+}
+
+func newPathAlias(pattern, target, base string) PathAlias {
+	alias := strings.TrimSuffix(pattern, "/*")
+	alias = strings.TrimSuffix(alias, "*")
+
+	target = strings.TrimSuffix(target, "/*")
+	target = strings.TrimSuffix(target, "*")
+	target = strings.TrimPrefix(target, "./")
+	target = filepath.FromSlash(target)
+
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(base, target)
+	}
 
-	return nil, nil
+	return PathAlias{
+		Pattern: pattern,
+		Alias:   alias,
+		Target:  target,
+	}
 }
 
-func parseAliases(configPath string) ([]PathAlias, error) {
-	file, err := os.Open(configPath)
+func parseProjectConfig(configPath string) (*ProjectConfig, error) {
+	options, err := loadCompilerOptions(configPath, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	project := &ProjectConfig{Path: configPath, Extends: options.chain[1:], RootDirs: options.rootDirs}
+
+	pathsBase := options.baseURL
+	if pathsBase == "" {
+		pathsBase = options.pathsBase
+	}
+
+	for aliasPattern, targets := range options.paths {
+		if len(targets) == 0 {
+			continue
+		}
+		project.Aliases = append(project.Aliases, newPathAlias(aliasPattern, targets[0], pathsBase))
+	}
+
+	return project, nil
+}
+
+type compilerOptions struct {
+	chain     []string
+	baseURL   string
+	paths     map[string][]string
+	pathsBase string
+	rootDirs  []string
+}
+
+func loadCompilerOptions(configPath string, seen map[string]bool) (*compilerOptions, error) {
+	if seen[configPath] {
+		return nil, fmt.Errorf("circular extends in %s", configPath)
+	}
+	seen[configPath] = true
+	defer delete(seen, configPath)
+
+	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	var config TSConfig
-	if err := json.NewDecoder(file).Decode(&config); err != nil {
+	if err := json.Unmarshal(normalizeJSONC(data), &config); err != nil {
 		return nil, err
 	}
 
-	var aliases []PathAlias
 	baseDir := filepath.Dir(configPath)
 
-	baseURL := config.CompilerOptions.BaseURL
-	if baseURL == "" {
-		baseURL = "."
+	options := &compilerOptions{}
+	for _, extends := range config.Extends {
+		parentPath := resolveExtends(baseDir, extends)
+		if parentPath == "" {
+			return nil, fmt.Errorf("cannot find extended config '%s' from %s", extends, configPath)
+		}
+
+		parent, err := loadCompilerOptions(parentPath, seen)
+		if err != nil {
+			return nil, err
+		}
+		options.merge(parent)
+	}
+
+	options.chain = append([]string{configPath}, options.chain...)
+
+	if baseURL := config.CompilerOptions.BaseURL; baseURL != "" {
+		if !filepath.IsAbs(baseURL) {
+			baseURL = filepath.Join(baseDir, baseURL)
+		}
+		options.baseURL = baseURL
 	}
 
-	if !filepath.IsAbs(baseURL) {
-		baseURL = filepath.Join(baseDir, baseURL)
+	if config.CompilerOptions.Paths != nil {
+		options.paths = config.CompilerOptions.Paths
+		options.pathsBase = baseDir
 	}
 
-	for aliasPattern, targets := range config.CompilerOptions.Paths {
-		if len(targets) == 0 {
-			continue
+	if config.CompilerOptions.RootDirs != nil {
+		options.rootDirs = nil
+		for _, rootDir := range config.CompilerOptions.RootDirs {
+			if !filepath.IsAbs(rootDir) {
+				rootDir = filepath.Join(baseDir, rootDir)
+			}
+			options.rootDirs = append(options.rootDirs, rootDir)
 		}
+	}
 
-		alias := strings.TrimSuffix(aliasPattern, "/*")
-		alias = strings.TrimSuffix(alias, "*")
+	return options, nil
+}
 
-		target := targets[0]
-		target = strings.TrimSuffix(target, "/*")
-		target = strings.TrimSuffix(target, "*")
-		target = strings.TrimPrefix(target, "./")
+func (o *compilerOptions) merge(parent *compilerOptions) {
+	o.chain = append(o.chain, parent.chain...)
+	if parent.baseURL != "" {
+		o.baseURL = parent.baseURL
+	}
+	if parent.paths != nil {
+		o.paths = parent.paths
+		o.pathsBase = parent.pathsBase
+	}
+	if parent.rootDirs != nil {
+		o.rootDirs = parent.rootDirs
+	}
+}
 
-		var targetPath string
-		if filepath.IsAbs(target) {
-			targetPath = target
-		} else {
-			targetPath = filepath.Join(baseURL, target)
+func resolveExtends(baseDir, extends string) string {
+	if filepath.IsAbs(extends) || strings.HasPrefix(extends, ".") {
+		parentPath := extends
+		if !filepath.IsAbs(parentPath) {
+			parentPath = filepath.Join(baseDir, parentPath)
+		}
+		if filepath.Ext(parentPath) != ".json" {
+			parentPath += ".json"
 		}
+		return parentPath
+	}
 
-		aliases = append(aliases, PathAlias{
-			Alias:  alias,
-			Target: targetPath,
-		})
+	name := packageName(extends)
+	packageDir := findUpDir(absPath(baseDir), filepath.Join("node_modules", name))
+	if packageDir == "" {
+		return ""
 	}
 
-	return aliases, nil
+	if subpath := strings.TrimPrefix(extends, name); subpath != "" {
+		target := filepath.Join(packageDir, subpath)
+		for _, candidate := range []string{target, target + ".json", filepath.Join(target, "tsconfig.json")} {
+			if fileExists(candidate) {
+				return candidate
+			}
+		}
+		return ""
+	}
+
+	var manifest struct {
+		TSConfig string `json:"tsconfig"`
+	}
+	if data, err := os.ReadFile(filepath.Join(packageDir, "package.json")); err == nil {
+		json.Unmarshal(data, &manifest)
+	}
+	if manifest.TSConfig != "" && fileExists(filepath.Join(packageDir, manifest.TSConfig)) {
+		return filepath.Join(packageDir, manifest.TSConfig)
+	}
+	if candidate := filepath.Join(packageDir, "tsconfig.json"); fileExists(candidate) {
+		return candidate
+	}
+	return ""
+}
+
+func findUpDir(dir, name string) string {
+	for {
+		if candidate := filepath.Join(dir, name); isDir(candidate) {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func (p *ProjectConfig) rootDirCandidates(basePath string) []string {
+	var owner string
+	for _, rootDir := range p.RootDirs {
+		if isWithin(basePath, rootDir) && len(rootDir) > len(owner) {
+			owner = rootDir
+		}
+	}
+	if owner == "" {
+		return nil
+	}
+
+	rel, err := filepath.Rel(owner, basePath)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []string
+	for _, rootDir := range p.RootDirs {
+		if rootDir != owner {
+			candidates = append(candidates, filepath.Join(rootDir, rel))
+		}
+	}
+	return candidates
+}
+
+func isWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
 }
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseImportStatement(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want *ImportInfo
+	}{
+		{
+			stmt: `import Button from './Button'`,
+			want: &ImportInfo{Source: "./Button", Specifiers: []string{"Button"}, Names: []string{"default"}},
+		},
+		{
+			stmt: `import { Card, Panel as P } from "@/ui"`,
+			want: &ImportInfo{Source: "@/ui", Specifiers: []string{"Card", "P"}, Names: []string{"Card", "Panel"}},
+		},
+		{
+			stmt: `import Layout, { Header } from './layout'`,
+			want: &ImportInfo{Source: "./layout", Specifiers: []string{"Layout", "Header"}, Names: []string{"default", "Header"}},
+		},
+		{
+			stmt: `import * as Widgets from './widgets'`,
+			want: &ImportInfo{Source: "./widgets", Names: []string{"*"}, Namespace: "Widgets"},
+		},
+		{
+			stmt: `import D, * as NS from './C'`,
+			want: &ImportInfo{Source: "./C", Specifiers: []string{"D"}, Names: []string{"default", "*"}, Namespace: "NS"},
+		},
+		{
+			stmt: `import { type Props, Button } from './Button'`,
+			want: &ImportInfo{Source: "./Button", Specifiers: []string{"Button"}, Names: []string{"Button"}},
+		},
+		{
+			stmt: `import './globals.css'`,
+			want: &ImportInfo{Source: "./globals.css"},
+		},
+		{
+			stmt: `import type { Props } from './Button'`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stmt, func(t *testing.T) {
+			if got := parseImportStatement(tt.stmt); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseImportStatement() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseImports(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		lines   []int
+	}{
+		{
+			name:    "one per line",
+			content: "import A from './a'\nimport { B } from './b'\n",
+			want:    []string{"./a", "./b"},
+			lines:   []int{1, 2},
+		},
+		{
+			name:    "multi-line list with comments",
+			content: "import {\n  A, // first\n  /* second */ B,\n} from './ab'\n",
+			want:    []string{"./ab"},
+			lines:   []int{1},
+		},
+		{
+			name:    "after a statement on the same line",
+			content: "'use client'; import { A } from './a'\n",
+			want:    []string{"./a"},
+			lines:   []int{1},
+		},
+		{
+			name:    "import text in strings and comments",
+			content: "const s = \"import x from './no'\"\n// import y from './no'\nconst t = `import z from './no'`\nimport Real from './real'\n",
+			want:    []string{"./real"},
+			lines:   []int{4},
+		},
+		{
+			name:    "dynamic import is not a declaration",
+			content: "const Chart = dynamic(() => import('./Chart'))\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sources []string
+			var lines []int
+			for _, imp := range parseImports(splitLines(tt.content)) {
+				sources = append(sources, imp.Source)
+				lines = append(lines, imp.Line)
+			}
+			if !reflect.DeepEqual(sources, tt.want) || !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("parseImports() = %v at lines %v, want %v at lines %v", sources, lines, tt.want, tt.lines)
+			}
+		})
+	}
+}
+
+func TestParseImportsSpecifiers(t *testing.T) {
+	imports := parseImports(splitLines("import D, * as NS from './C'\n"))
+	if len(imports) != 1 {
+		t.Fatalf("parseImports() returned %d imports, want 1", len(imports))
+	}
+	if got := imports[0]; !reflect.DeepEqual(got.Specifiers, []string{"D"}) || got.Namespace != "NS" {
+		t.Errorf("parseImports() = %+v, want specifier D and namespace NS", got)
+	}
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScanFileFindings(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"C.tsx":    "'use client'\nexport default function D() { return null }\nexport function X() { return null }\n",
+		"page.tsx": "import D, * as NS from './C'\nexport default function P() { return <><D /><NS.X /></> }\n",
+	})
+
+	result := &ScanResult{}
+	if err := scanFile(filepath.Join(root, "page.tsx"), DefaultConfig(), false, result); err != nil {
+		t.Fatal(err)
+	}
+	var components []string
+	for _, f := range result.Findings {
+		components = append(components, f.Component)
+	}
+	if got := strings.Join(components, ","); got != "D,NS.X" {
+		t.Errorf("findings for components %q, want %q", got, "D,NS.X")
+	}
+}
+
+func TestResolveImportPath(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"tsconfig.json":                 `{"compilerOptions": {"baseUrl": ".", "paths": {"@/*": ["./src/*"], "~ui": ["./src/ui/index.ts"]}}}`,
+		"package.json":                  `{"imports": {"#lib/*": "./src/lib/*.ts"}}`,
+		"src/app/page.tsx":              "",
+		"src/components/Button.tsx":     "",
+		"src/components/Card/index.tsx": "",
+		"src/components/util.ts":        "",
+		"src/ui/index.ts":               "",
+		"src/lib/db.ts":                 "",
+	})
+	baseDir := filepath.Join(root, "src", "app")
+	src := func(name string) string {
+		return filepath.Join(root, "src", filepath.FromSlash(name))
+	}
+
+	tests := []struct {
+		source string
+		want   []string
+		local  bool
+	}{
+		{source: "../components/Button", want: []string{src("components/Button.tsx")}, local: true},
+		{source: "../components/Card", want: []string{src("components/Card/index.tsx")}, local: true},
+		{source: "../components/util.js", want: []string{src("components/util.ts")}, local: true},
+		{source: "@/components/Button", want: []string{src("components/Button.tsx")}, local: true},
+		{source: "~ui", want: []string{src("ui/index.ts")}, local: true},
+		{source: "#lib/db", want: []string{src("lib/db.ts")}, local: true},
+		{source: "../components/Missing", local: true},
+		{source: "react"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			config := DefaultConfig()
+			project, err := loadProjectConfig(filepath.Join(baseDir, "page.tsx"), config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			importMap, _ := loadImportMap(baseDir, config)
+
+			got := resolveImportPath(baseDir, tt.source, project, importMap, config)
+			if !reflect.DeepEqual(got.Paths, tt.want) || got.Local != tt.local {
+				t.Errorf("resolveImportPath(%q) = %v (local %v), want %v (local %v)", tt.source, got.Paths, got.Local, tt.want, tt.local)
+			}
+		})
+	}
+}
+
+func TestScanContentTagsInCodeOnly(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"Button.tsx": "'use client'\nexport function Button() { return null }\nexport function Bütton() { return null }\n",
+	})
+	header := "import { Button, Bütton } from './Button'\n"
+
+	tests := []struct {
+		name    string
+		body    string
+		columns []int
+	}{
+		{name: "line comment", body: "// <Bütton />"},
+		{name: "block comment", body: "/* <Button /> */"},
+		{name: "JSX comment", body: "const a = <div>{/* <Button /> */}</div>"},
+		{name: "double-quoted string", body: `const s = "<Button />"`},
+		{name: "single-quoted string", body: "const s = '<Button />'"},
+		{name: "template literal", body: "const s = `<Button />`"},
+		{name: "prop reference in a string", body: `const s = "x={Button}"`},
+		{name: "code after a comment", body: "/* <Button /> */ const a = <Button />", columns: []int{28}},
+		{name: "template interpolation", body: "const s = `${<Button />}`", columns: []int{14}},
+		{name: "apostrophe in JSX text", body: "const a = <p>Don't <Button /></p>", columns: []int{20}},
+		{name: "prop reference", body: "const a = <Slot as={Button} />", columns: []int{21}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ScanResult{}
+			path := filepath.Join(root, "page.tsx")
+			if err := scanContent(path, []byte(header+tt.body+"\n"), DefaultConfig(), false, result); err != nil {
+				t.Fatal(err)
+			}
+			var columns []int
+			for _, f := range result.Findings {
+				columns = append(columns, f.Column)
+			}
+			if !reflect.DeepEqual(columns, tt.columns) {
+				t.Errorf("findings at columns %v, want %v", columns, tt.columns)
+			}
+		})
+	}
+}
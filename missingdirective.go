@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var clientHooks = map[string]bool{
+	"useState":             true,
+	"useReducer":           true,
+	"useEffect":            true,
+	"useLayoutEffect":      true,
+	"useInsertionEffect":   true,
+	"useRef":               true,
+	"useContext":           true,
+	"useTransition":        true,
+	"useDeferredValue":     true,
+	"useSyncExternalStore": true,
+	"useOptimistic":        true,
+}
+
+var declarationKeywords = map[string]bool{
+	"async":    true,
+	"class":    true,
+	"const":    true,
+	"export":   true,
+	"function": true,
+	"let":      true,
+	"var":      true,
+}
+
+func findMissingDirective(filePath string, lines []string) []Finding {
+	text := strings.Join(lines, "\n")
+	tokens := tokenize(text)
+
+	var findings []Finding
+	report := func(t token, message string) {
+		findings = append(findings, Finding{
+			File:     filePath,
+			Line:     t.line,
+			Column:   t.offset - strings.LastIndex(text[:t.offset], "\n"),
+			Content:  lines[t.line-1],
+			Rule:     RuleMissingClientDirective,
+			Severity: SeverityWarning,
+			Message:  message,
+		})
+	}
+
+	depth := 0
+	var hookBodies []int
+	pendingHook := false
+	pendingDepth := 0
+	for i, t := range tokens {
+		if pendingHook && t.lineStart && depth == pendingDepth && declarationKeywords[t.text] {
+			pendingHook = false
+		}
+
+		switch {
+		case t.kind == tokenPunct && t.text == "{":
+			if pendingHook {
+				hookBodies = append(hookBodies, depth)
+				pendingHook = false
+			}
+			depth++
+		case t.kind == tokenPunct && t.text == "}":
+			depth--
+			if n := len(hookBodies); n > 0 && hookBodies[n-1] == depth {
+				hookBodies = hookBodies[:n-1]
+			}
+		case t.kind == tokenPunct && t.text == ";":
+			pendingHook = false
+		case t.kind != tokenIdent:
+		case isHookDeclaration(tokens, i):
+			pendingHook = true
+			pendingDepth = depth
+		case clientHooks[t.text] && tokenText(tokens, i+1) == "(" && !isMemberOf(tokens, i) &&
+			!pendingHook && len(hookBodies) == 0:
+			report(t, fmt.Sprintf("server file calls %s() without a client directive", t.text))
+		case isEventHandlerName(t.text) && tokenText(tokens, i+1) == "=" && tokenText(tokens, i+2) == "{":
+			report(t, fmt.Sprintf("server file passes an %s handler without a client directive", t.text))
+		}
+	}
+	return findings
+}
+
+func isHookDeclaration(tokens []token, i int) bool {
+	name := tokens[i].text
+	if len(name) < 4 || !strings.HasPrefix(name, "use") || name[3] < 'A' || name[3] > 'Z' {
+		return false
+	}
+	switch tokenText(tokens, i-1) {
+	case "function":
+		return true
+	case "const", "let", "var":
+		return tokenText(tokens, i+1) == "="
+	}
+	return false
+}
+
+func isMemberOf(tokens []token, i int) bool {
+	return tokenText(tokens, i-1) == "." && tokenText(tokens, i-2) != "React"
+}
+
+func isEventHandlerName(name string) bool {
+	return len(name) > 2 && strings.HasPrefix(name, "on") && name[2] >= 'A' && name[2] <= 'Z'
+}
+
+func tokenText(tokens []token, i int) string {
+	if i < 0 || i >= len(tokens) {
+		return ""
+	}
+	return tokens[i].text
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"sort"
+)
+
+type ModuleGraph struct {
+	Nodes []ModuleNode `json:"nodes"`
+	Edges []ModuleEdge `json:"edges"`
+}
+
+type ModuleNode struct {
+	File      string           `json:"file"`
+	Directive bool             `json:"directive"`
+	IsClient  bool             `json:"isClient"`
+	IsServer  bool             `json:"isServer"`
+	Scanned   bool             `json:"scanned"`
+	Size      int64            `json:"size"`
+	Lines     int              `json:"lines"`
+	External  []ExternalImport `json:"external"`
+}
+
+type ExternalImport struct {
+	Source     string   `json:"source"`
+	Specifiers []string `json:"specifiers"`
+}
+
+type ModuleEdge struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Source     string   `json:"source"`
+	Specifiers []string `json:"specifiers"`
+	Names      []string `json:"names"`
+}
+
+func buildModuleGraph(graph *ImportGraph, config *Config) *ModuleGraph {
+	paths := make([]string, 0, len(graph.Nodes))
+	for path := range graph.Nodes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	server := make(map[string]bool)
+	var queue []string
+	for _, path := range paths {
+		if node := graph.Nodes[path]; node.loaded && !node.IsClient && len(node.Importers) == 0 {
+			queue = append(queue, path)
+		}
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		node := graph.Nodes[path]
+		if server[path] || node.IsClient {
+			continue
+		}
+		server[path] = true
+		for _, edge := range node.Imports {
+			queue = append(queue, edge.Target)
+		}
+	}
+
+	modules := &ModuleGraph{Nodes: []ModuleNode{}, Edges: []ModuleEdge{}}
+	for _, path := range paths {
+		node := graph.Nodes[path]
+		directive := node.IsClient
+		if !node.loaded {
+			directive = isClientFile(path, config)
+		}
+		module := ModuleNode{
+			File:      displayPath(path),
+			Directive: directive,
+			IsClient:  directive || graph.boundaryChain(path) != nil,
+			IsServer:  server[path],
+			Scanned:   node.loaded,
+			Size:      node.Size,
+			Lines:     node.Lines,
+			External:  []ExternalImport{},
+		}
+		for _, edge := range node.External {
+			module.External = append(module.External, ExternalImport{Source: edge.Source, Specifiers: nonNil(edge.Specifiers)})
+		}
+		modules.Nodes = append(modules.Nodes, module)
+
+		for _, edge := range node.Imports {
+			modules.Edges = append(modules.Edges, ModuleEdge{
+				From:       displayPath(path),
+				To:         displayPath(edge.Target),
+				Source:     edge.Source,
+				Specifiers: nonNil(edge.Specifiers),
+				Names:      nonNil(edge.Names),
+			})
+		}
+	}
+	return modules
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
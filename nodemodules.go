@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var exportConditions = map[string]bool{
+	"react-server": true,
+	"import":       true,
+	"module":       true,
+	"require":      true,
+	"node":         true,
+	"default":      true,
+}
+
+type packageManifest struct {
+	Exports json.RawMessage `json:"exports"`
+	Source  string          `json:"source"`
+	Module  string          `json:"module"`
+	Main    string          `json:"main"`
+}
+
+type jsonMember struct {
+	Key   string
+	Value json.RawMessage
+}
+
+func resolveNodeModule(baseDir, specifier string, config *Config) []string {
+	name := packageName(specifier)
+	manifestPath := findUp(absPath(baseDir), filepath.Join("node_modules", name, "package.json"))
+	if manifestPath == "" {
+		explainf(config, "  node_modules/%s: not found", name)
+		return nil
+	}
+	dir := filepath.Dir(manifestPath)
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil
+	}
+	var manifest packageManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		explainf(config, "  %s: %v", manifestPath, err)
+		return nil
+	}
+
+	subpath := "." + strings.TrimPrefix(specifier, name)
+	if manifest.Exports != nil {
+		target, ok := resolvePackageExports(manifest.Exports, subpath)
+		if !ok {
+			explainf(config, "  %s: '%s' not exported", manifestPath, subpath)
+			return nil
+		}
+		explainf(config, "  %s exports '%s' -> %s", manifestPath, subpath, target)
+		paths, _ := expandPath(filepath.Join(dir, target), config)
+		return paths
+	}
+
+	if subpath != "." {
+		paths, _ := expandPath(filepath.Join(dir, subpath), config)
+		return paths
+	}
+	for _, entry := range []string{manifest.Module, manifest.Main, "index"} {
+		if entry == "" {
+			continue
+		}
+		if paths, _ := expandPath(filepath.Join(dir, entry), config); len(paths) > 0 {
+			explainf(config, "  %s entry -> %s", manifestPath, entry)
+			return paths
+		}
+	}
+	return nil
+}
+
+func resolvePackageExports(exports json.RawMessage, subpath string) (string, bool) {
+	members, isObject := jsonObjectMembers(exports)
+	if !isObject || len(members) == 0 || !strings.HasPrefix(members[0].Key, ".") {
+		if subpath != "." {
+			return "", false
+		}
+		return resolveExportTarget(exports, "")
+	}
+
+	return resolvePatternMembers(members, subpath)
+}
+
+// resolvePatternMembers resolves key against the members of an "exports" or
+// "imports" object the way Node does: an exact key wins, otherwise the "*"
+// pattern with the longest prefix, then the longest key, is used regardless
+// of the order the patterns are listed in.
+func resolvePatternMembers(members []jsonMember, key string) (string, bool) {
+	for _, member := range members {
+		if member.Key == key {
+			return resolveExportTarget(member.Value, "")
+		}
+	}
+
+	best, match := -1, ""
+	for i, member := range members {
+		prefix, suffix, ok := strings.Cut(member.Key, "*")
+		if !ok || strings.Contains(suffix, "*") || !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, suffix) || len(key) < len(prefix)+len(suffix) {
+			continue
+		}
+		if best >= 0 {
+			bestPrefix := strings.Index(members[best].Key, "*")
+			if len(prefix) < bestPrefix || len(prefix) == bestPrefix && len(member.Key) <= len(members[best].Key) {
+				continue
+			}
+		}
+		best, match = i, key[len(prefix):len(key)-len(suffix)]
+	}
+	if best < 0 {
+		return "", false
+	}
+	return resolveExportTarget(members[best].Value, match)
+}
+
+func resolveExportTarget(target json.RawMessage, match string) (string, bool) {
+	var path string
+	if err := json.Unmarshal(target, &path); err == nil {
+		return strings.ReplaceAll(path, "*", match), true
+	}
+
+	var alternatives []json.RawMessage
+	if err := json.Unmarshal(target, &alternatives); err == nil {
+		for _, alternative := range alternatives {
+			if path, ok := resolveExportTarget(alternative, match); ok {
+				return path, true
+			}
+		}
+		return "", false
+	}
+
+	members, _ := jsonObjectMembers(target)
+	for _, member := range members {
+		if exportConditions[member.Key] {
+			if path, ok := resolveExportTarget(member.Value, match); ok {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
+
+func jsonObjectMembers(data json.RawMessage) ([]jsonMember, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+
+	var members []jsonMember
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return members, true
+		}
+		key, _ := token.(string)
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return members, true
+		}
+		members = append(members, jsonMember{Key: key, Value: value})
+	}
+	return members, true
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type BudgetSummary struct {
+	Findings   int    `json:"findings"`
+	Budget     int    `json:"budget"`
+	Exceeded   int    `json:"exceeded"`
+	Unresolved int    `json:"unresolved"`
+	Root       string `json:"root"`
+	Distinct   bool   `json:"distinct,omitempty"`
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+func checkBudget(result *ScanResult, maxFindings int, root string) *BudgetSummary {
+	if maxFindings < 0 {
+		return nil
+	}
+
+	count := 0
+	for _, f := range result.Findings {
+		if f.Rule == RuleClientUsage {
+			count++
+		}
+	}
+	if count <= maxFindings {
+		return nil
+	}
+
+	return &BudgetSummary{
+		Findings:   count,
+		Budget:     maxFindings,
+		Exceeded:   count - maxFindings,
+		Unresolved: len(result.Unresolved),
+		Root:       root,
+	}
+}
+
+func checkPathBudgets(result *ScanResult, fileConfig *FileConfig) ([]*BudgetSummary, error) {
+	if fileConfig == nil || len(fileConfig.Budgets) == 0 {
+		return nil, nil
+	}
+
+	dir := absPath(filepath.Dir(fileConfig.Path))
+	counts := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, f := range result.Findings {
+		file := absPath(f.File)
+		if f.Rule != RuleClientUsage || seen[file+"\x00"+f.Component] {
+			continue
+		}
+		seen[file+"\x00"+f.Component] = true
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check budgets for %s: %w", f.File, err)
+		}
+		rel = filepath.ToSlash(rel)
+		for pattern := range fileConfig.Budgets {
+			if matchGlob(pattern, rel) {
+				counts[pattern]++
+			}
+		}
+	}
+
+	var patterns []string
+	for pattern := range fileConfig.Budgets {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	var breaches []*BudgetSummary
+	for _, pattern := range patterns {
+		budget := fileConfig.Budgets[pattern].MaxClientComponents
+		if counts[pattern] <= budget {
+			continue
+		}
+		breaches = append(breaches, &BudgetSummary{
+			Findings:   counts[pattern],
+			Budget:     budget,
+			Exceeded:   counts[pattern] - budget,
+			Unresolved: len(result.Unresolved),
+			Root:       pattern,
+			Distinct:   true,
+		})
+	}
+	return breaches, nil
+}
+
+func (s *BudgetSummary) String() string {
+	counted := "client component usages"
+	if s.Distinct {
+		counted = "distinct client components"
+	}
+	return fmt.Sprintf("boundary budget exceeded in %s: %d %s (budget %d, +%d)",
+		s.Root, s.Findings, counted, s.Budget, s.Exceeded)
+}
+
+func sendNotification(url, format string, summary *BudgetSummary) error {
+	var payload interface{}
+	switch format {
+	case "json":
+		payload = summary
+	case "slack":
+		payload = slackPayload{Text: ":warning: " + summary.String()}
+	default:
+		return fmt.Errorf("unknown notify format: %s", format)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify %s: %s", url, resp.Status)
+	}
+	return nil
+}
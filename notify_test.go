@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckPathBudgets(t *testing.T) {
+	root := t.TempDir()
+	usage := func(file, component string) Finding {
+		return Finding{File: file, Rule: RuleClientUsage, Component: component}
+	}
+	fileConfig := &FileConfig{
+		Path: filepath.Join(root, configFileName),
+		Budgets: map[string]PathBudget{
+			"app/marketing/**": {MaxClientComponents: 1},
+			"app/shop/**":      {MaxClientComponents: 2},
+		},
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	relative, err := filepath.Rel(wd, filepath.Join(root, "app", "shop", "cart.tsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var findings []Finding
+	for i := 0; i < 10; i++ {
+		findings = append(findings, usage(filepath.Join(root, "app", "marketing", "page.tsx"), "Hero"))
+	}
+	findings = append(findings,
+		usage(filepath.Join(root, "app", "marketing", "page.tsx"), "Banner"),
+		usage(filepath.Join(root, "app", "shop", "page.tsx"), "Cart"),
+		usage(relative, "Cart"),
+		Finding{File: filepath.Join(root, "app", "shop", "page.tsx"), Rule: RuleUnresolvedImport},
+	)
+
+	breaches, err := checkPathBudgets(&ScanResult{Findings: findings}, fileConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breaches) != 1 {
+		t.Fatalf("got %d breaches, want 1: %v", len(breaches), breaches)
+	}
+	got := breaches[0]
+	if got.Root != "app/marketing/**" || got.Findings != 2 || got.Exceeded != 1 {
+		t.Errorf("breach = %+v, want app/marketing/** with 2 distinct components, exceeded by 1", got)
+	}
+	if want := "boundary budget exceeded in app/marketing/**: 2 distinct client components (budget 1, +1)"; got.String() != want {
+		t.Errorf("String() = %q, want %q", got.String(), want)
+	}
+}
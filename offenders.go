@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+type ClosureSize struct {
+	Boundary string  `json:"boundary"`
+	Files    int     `json:"files"`
+	Bytes    int64   `json:"bytes"`
+	Share    float64 `json:"share"`
+}
+
+func runOffendersCommand(args []string) error {
+	command := newGraphCommand("offenders")
+	limit := command.flags.Int("limit", 10, "number of boundaries to show (0 for all)")
+	config, graph, err := command.parse(args)
+	if err != nil {
+		return err
+	}
+
+	offenders := rankClientClosures(graph, config, *command.verbose)
+	if *limit > 0 && len(offenders) > *limit {
+		offenders = offenders[:*limit]
+	}
+
+	return command.write(os.Stdout, offenders, func(w io.Writer) error {
+		return writeOffendersText(w, offenders)
+	})
+}
+
+func rankClientClosures(graph *ImportGraph, config *Config, verbose bool) []ClosureSize {
+	var sizes []ClosureSize
+	bundle := make(map[string]int64)
+
+	for _, boundary := range graph.boundaries() {
+		size := ClosureSize{Boundary: displayPath(boundary)}
+		for _, node := range graph.closure(boundary, config, verbose) {
+			size.Files++
+			size.Bytes += node.Size
+			bundle[node.Path] = node.Size
+		}
+		sizes = append(sizes, size)
+	}
+
+	var total int64
+	for _, size := range bundle {
+		total += size
+	}
+	for i := range sizes {
+		if total > 0 {
+			sizes[i].Share = float64(sizes[i].Bytes) / float64(total)
+		}
+	}
+
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].Bytes != sizes[j].Bytes {
+			return sizes[i].Bytes > sizes[j].Bytes
+		}
+		return sizes[i].Boundary < sizes[j].Boundary
+	})
+
+	return sizes
+}
+
+func writeOffendersText(w io.Writer, offenders []ClosureSize) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tBOUNDARY\tFILES\tBYTES\tSHARE")
+	for i, offender := range offenders {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%.0f%%\n", i+1, offender.Boundary, offender.Files, offender.Bytes, offender.Share*100)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type PackageUsage struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	License string   `json:"license,omitempty"`
+	Uses    int      `json:"uses"`
+	Files   []string `json:"files"`
+}
+
+func runPackagesCommand(args []string) error {
+	command := newGraphCommand("packages")
+	config, graph, err := command.parse(args)
+	if err != nil {
+		return err
+	}
+
+	lock, err := loadLockfile(*command.path)
+	if err != nil && *command.verbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read %s: %v\n", lock.Path, err)
+	}
+
+	inventory := clientPackageInventory(graph, config, *command.verbose)
+	for i := range inventory {
+		usage := &inventory[i]
+		usage.Version = strings.Join(lock.Versions[usage.Name], ", ")
+		if pkg := readInstalledPackage(*command.path, usage.Name); pkg != nil {
+			if usage.Version == "" {
+				usage.Version = pkg.Version
+			}
+			usage.License = pkg.license()
+		}
+	}
+
+	return command.write(os.Stdout, inventory, func(w io.Writer) error {
+		return writePackagesText(w, inventory)
+	})
+}
+
+func clientPackageInventory(graph *ImportGraph, config *Config, verbose bool) []PackageUsage {
+	bundle := make(map[string]*GraphNode)
+	for _, clientPath := range graph.clientFiles() {
+		for _, node := range graph.closure(clientPath, config, verbose) {
+			bundle[node.Path] = node
+		}
+	}
+
+	usages := make(map[string]*PackageUsage)
+	for _, node := range bundle {
+		for _, edge := range node.External {
+			name := packageName(edge.Source)
+			usage, ok := usages[name]
+			if !ok {
+				usage = &PackageUsage{Name: name}
+				usages[name] = usage
+			}
+			usage.Uses++
+			if file := displayPath(node.Path); !containsString(usage.Files, file) {
+				usage.Files = append(usage.Files, file)
+			}
+		}
+	}
+
+	inventory := make([]PackageUsage, 0, len(usages))
+	for _, usage := range usages {
+		sort.Strings(usage.Files)
+		inventory = append(inventory, *usage)
+	}
+	sort.Slice(inventory, func(i, j int) bool {
+		if inventory[i].Uses != inventory[j].Uses {
+			return inventory[i].Uses > inventory[j].Uses
+		}
+		return inventory[i].Name < inventory[j].Name
+	})
+
+	return inventory
+}
+
+func writePackagesText(w io.Writer, inventory []PackageUsage) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tVERSION\tLICENSE\tUSES\tFILES")
+	for _, usage := range inventory {
+		version := usage.Version
+		if version == "" {
+			version = "-"
+		}
+		license := usage.License
+		if license == "" {
+			license = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n", usage.Name, version, license, usage.Uses, len(usage.Files))
+	}
+	return tw.Flush()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	serverOnlyPackage = "server-only"
+	clientOnlyPackage = "client-only"
+)
+
+var serverBuiltins = map[string]bool{
+	"async_hooks":    true,
+	"child_process":  true,
+	"cluster":        true,
+	"dgram":          true,
+	"dns":            true,
+	"fs":             true,
+	"fs/promises":    true,
+	"http2":          true,
+	"inspector":      true,
+	"module":         true,
+	"net":            true,
+	"perf_hooks":     true,
+	"readline":       true,
+	"repl":           true,
+	"tls":            true,
+	"v8":             true,
+	"vm":             true,
+	"worker_threads": true,
+}
+
+func findPoisonedImports(filePath string, lines []string, imports []ImportInfo, resolutions []Resolution, isClient, isServer bool, config *Config) []Finding {
+	var findings []Finding
+	report := func(imp ImportInfo, rule, message string) {
+		findings = append(findings, Finding{
+			File:         filePath,
+			Line:         imp.Line,
+			Content:      lines[imp.Line-1],
+			Rule:         rule,
+			Severity:     SeverityError,
+			Message:      message,
+			ImportSource: imp.Source,
+		})
+	}
+
+	for i, imp := range imports {
+		switch {
+		case isClient && imp.Source == serverOnlyPackage:
+			report(imp, RuleServerOnlyImport, fmt.Sprintf("client file imports '%s'", serverOnlyPackage))
+		case isClient && isServerBuiltin(imp.Source):
+			report(imp, RuleServerOnlyImport, fmt.Sprintf("client file imports Node built-in '%s'", imp.Source))
+		case isClient && importsPackage(resolutions[i], serverOnlyPackage, config):
+			report(imp, RuleServerOnlyImport, fmt.Sprintf("client file imports '%s', which imports '%s'", imp.Source, serverOnlyPackage))
+		case isServer && imp.Source == clientOnlyPackage:
+			report(imp, RuleClientOnlyImport, fmt.Sprintf("server file imports '%s'", clientOnlyPackage))
+		case isServer && importsPackage(resolutions[i], clientOnlyPackage, config):
+			report(imp, RuleClientOnlyImport, fmt.Sprintf("server file imports '%s', which imports '%s'", imp.Source, clientOnlyPackage))
+		}
+	}
+	return findings
+}
+
+func isServerBuiltin(source string) bool {
+	return strings.HasPrefix(source, "node:") || serverBuiltins[source]
+}
+
+func importsPackage(resolution Resolution, pkg string, config *Config) bool {
+	for _, path := range resolution.Paths {
+		if table := loadExportTable(path, config); table != nil && containsString(table.Imports, pkg) {
+			return true
+		}
+	}
+	return false
+}
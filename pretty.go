@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+)
+
+type prettyOptions struct {
+	Context int
+	Color   bool
+}
+
+var pretty prettyOptions
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func colorEnabled(mode string, f *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && isTerminal(f), nil
+	default:
+		return false, fmt.Errorf("unknown color mode: %s (want auto, always or never)", mode)
+	}
+}
+
+func (o prettyOptions) paint(color, s string) string {
+	if !o.Color || s == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+func writePrettyReport(w io.Writer, result *ScanResult) error {
+	for start := 0; start < len(result.Findings); {
+		end := start + 1
+		for end < len(result.Findings) && result.Findings[end].File == result.Findings[start].File {
+			end++
+		}
+		if start > 0 {
+			fmt.Fprintln(w)
+		}
+		writePrettyFile(w, result.Findings[start:end])
+		start = end
+	}
+	return nil
+}
+
+func writePrettyFile(w io.Writer, findings []Finding) {
+	fmt.Fprintln(w, pretty.paint(ansiMagenta+ansiBold, findings[0].File))
+
+	var lines []string
+	if pretty.Context > 0 {
+		if content, err := os.ReadFile(findings[0].File); err == nil {
+			lines = splitLines(string(content))
+		}
+	}
+
+	printed := 0
+	for start := 0; start < len(findings); {
+		end := start + 1
+		for end < len(findings) && findings[end].Line == findings[start].Line {
+			end++
+		}
+		group := findings[start:end]
+		line := group[0].Line
+
+		if lines != nil && line <= len(lines) && strings.TrimRight(lines[line-1], "\r") == strings.TrimRight(group[0].Content, "\r") {
+			from := line - pretty.Context
+			if from < 1 {
+				from = 1
+			}
+			if printed > 0 && from > printed+1 {
+				fmt.Fprintln(w, pretty.paint(ansiDim, "--"))
+			}
+			if from <= printed {
+				from = printed + 1
+			}
+			for n := from; n < line; n++ {
+				fmt.Fprintf(w, "%s-%s\n", pretty.paint(ansiGreen, fmt.Sprint(n)), lines[n-1])
+			}
+			writePrettyLine(w, group)
+			to := line + pretty.Context
+			if to > len(lines) {
+				to = len(lines)
+			}
+			if start+len(group) < len(findings) && findings[end].Line-pretty.Context <= to {
+				to = findings[end].Line - 1
+			}
+			for n := line + 1; n <= to; n++ {
+				fmt.Fprintf(w, "%s-%s\n", pretty.paint(ansiGreen, fmt.Sprint(n)), lines[n-1])
+			}
+			printed = to
+			if printed < line {
+				printed = line
+			}
+		} else {
+			writePrettyLine(w, group)
+		}
+		start = end
+	}
+}
+
+func writePrettyLine(w io.Writer, group []Finding) {
+	f := group[0]
+	position := fmt.Sprint(f.Line)
+	if f.Column > 0 {
+		position += fmt.Sprintf(":%d", f.Column)
+	}
+
+	var notes []string
+	for _, f := range group {
+		if f.Message != "" {
+			color := ansiYellow
+			if f.Severity == SeverityError {
+				color = ansiRed
+			}
+			notes = append(notes, fmt.Sprintf("%s: %s %s", pretty.paint(color+ansiBold, f.Severity), f.Message, pretty.paint(ansiDim, "["+f.Rule+"]")))
+		}
+		if f.Route != "" {
+			notes = appendUnique(notes, pretty.paint(ansiDim, "route "+f.Route))
+		}
+		if f.ClientSubtree != nil {
+			notes = append(notes, pretty.paint(ansiDim, fmt.Sprintf("client subtree: %d files, %d lines, %d bytes", f.ClientSubtree.Files, f.ClientSubtree.Lines, f.ClientSubtree.Bytes)))
+		}
+	}
+
+	fmt.Fprintf(w, "%s:%s\n", pretty.paint(ansiGreen, position), highlightFindings(strings.TrimRight(f.Content, "\r"), group))
+	indent := strings.Repeat(" ", len(position)+1)
+	for _, note := range notes {
+		fmt.Fprintf(w, "%s%s\n", indent, note)
+	}
+}
+
+func highlightFindings(content string, group []Finding) string {
+	type span struct{ start, end int }
+	var spans []span
+	for _, f := range group {
+		start, end := -1, -1
+		switch {
+		case f.Column > 0 && f.Component != "" && strings.HasPrefix(content[min(f.Column-1, len(content)):], "<"+f.Component):
+			start, end = f.Column-1, f.Column+len(f.Component)
+		case f.Column > 0 && f.Column <= len(content):
+			start = f.Column - 1
+			end = start
+			for end < len(content) && isIdentPart(content[end:]) {
+				end++
+			}
+		case f.ImportSource != "":
+			for _, quote := range []string{"'", `"`, "`"} {
+				if i := strings.Index(content, quote+f.ImportSource+quote); i >= 0 {
+					start, end = i+1, i+1+len(f.ImportSource)
+					break
+				}
+			}
+		}
+		if start >= 0 && end > start {
+			spans = append(spans, span{start, end})
+		}
+	}
+	if !pretty.Color || len(spans) == 0 {
+		return content
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s.start < last {
+			continue
+		}
+		b.WriteString(content[last:s.start])
+		b.WriteString(pretty.paint(ansiRed+ansiBold, content[s.start:s.end]))
+		last = s.end
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
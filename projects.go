@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const maxReferenceDepth = 8
+
+type projectMemo struct {
+	mu       sync.Mutex
+	dirs     map[string]string
+	projects map[string]*projectEntry
+	scopes   map[string]*projectScope
+}
+
+type projectEntry struct {
+	project *ProjectConfig
+	err     error
+}
+
+type projectScope struct {
+	Dir        string
+	Include    []string
+	Exclude    []string
+	Files      []string
+	References []string
+}
+
+func newProjectMemo() *projectMemo {
+	return &projectMemo{
+		dirs:     make(map[string]string),
+		projects: make(map[string]*projectEntry),
+		scopes:   make(map[string]*projectScope),
+	}
+}
+
+func (m *projectMemo) configPath(filePath string) string {
+	if m == nil {
+		return findProjectConfig(filePath)
+	}
+
+	key := filepath.Dir(filePath)
+	if isTypeScriptFile(filePath) {
+		key += "\x00ts"
+	}
+
+	m.mu.Lock()
+	configPath, ok := m.dirs[key]
+	m.mu.Unlock()
+	if ok {
+		return configPath
+	}
+
+	configPath = findProjectConfig(filePath)
+	m.mu.Lock()
+	m.dirs[key] = configPath
+	m.mu.Unlock()
+	return configPath
+}
+
+func (m *projectMemo) parse(configPath string) (*ProjectConfig, error) {
+	if m == nil {
+		return parseProjectConfig(configPath)
+	}
+
+	m.mu.Lock()
+	entry, ok := m.projects[configPath]
+	m.mu.Unlock()
+	if ok {
+		return entry.project, entry.err
+	}
+
+	project, err := parseProjectConfig(configPath)
+	m.mu.Lock()
+	m.projects[configPath] = &projectEntry{project: project, err: err}
+	m.mu.Unlock()
+	return project, err
+}
+
+func (m *projectMemo) scope(configPath string) *projectScope {
+	if m == nil {
+		return loadProjectScope(configPath)
+	}
+
+	m.mu.Lock()
+	scope, ok := m.scopes[configPath]
+	m.mu.Unlock()
+	if ok {
+		return scope
+	}
+
+	scope = loadProjectScope(configPath)
+	m.mu.Lock()
+	m.scopes[configPath] = scope
+	m.mu.Unlock()
+	return scope
+}
+
+func (m *projectMemo) referenced(configPath, filePath string) string {
+	for depth := 0; depth < maxReferenceDepth; depth++ {
+		scope := m.scope(configPath)
+		if scope == nil {
+			return configPath
+		}
+
+		next := ""
+		for _, reference := range scope.References {
+			if referenced := m.scope(reference); referenced != nil && referenced.includes(filePath) {
+				next = reference
+				break
+			}
+		}
+		if next == "" {
+			return configPath
+		}
+		configPath = next
+	}
+	return configPath
+}
+
+func (m *projectMemo) forget() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dirs = make(map[string]string)
+	m.projects = make(map[string]*projectEntry)
+	m.scopes = make(map[string]*projectScope)
+}
+
+func loadProjectScope(configPath string) *projectScope {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil
+	}
+	var config TSConfig
+	if err := json.Unmarshal(normalizeJSONC(data), &config); err != nil {
+		return nil
+	}
+
+	dir := absPath(filepath.Dir(configPath))
+	scope := &projectScope{Dir: dir, Include: config.Include, Exclude: config.Exclude, Files: config.Files}
+	for _, reference := range config.References {
+		path := reference.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "tsconfig.json")
+		}
+		scope.References = append(scope.References, path)
+	}
+	return scope
+}
+
+func (s *projectScope) includes(filePath string) bool {
+	rel, err := filepath.Rel(s.Dir, absPath(filePath))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, file := range s.Files {
+		if strings.TrimPrefix(file, "./") == rel {
+			return true
+		}
+	}
+
+	include := s.Include
+	if len(include) == 0 && len(s.Files) == 0 {
+		include = []string{"**/*"}
+	}
+	return matchesProjectPattern(include, rel) && !matchesProjectPattern(s.Exclude, rel)
+}
+
+func matchesProjectPattern(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+		if matchGlob(pattern, rel) || matchGlob(pattern+"/**", rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func isProjectConfigFile(path string) bool {
+	name := filepath.Base(path)
+	return filepath.Ext(name) == ".json" && (strings.HasPrefix(name, "tsconfig") || strings.HasPrefix(name, "jsconfig"))
+}
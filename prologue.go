@@ -0,0 +1,117 @@
+package main
+
+import (
+	"io"
+	"strings"
+)
+
+type prologueDirective struct {
+	Text string
+	Line int
+}
+
+func directivePrologue(content []byte) []prologueDirective {
+	s := strings.TrimPrefix(string(content), "\ufeff")
+	pos, line := 0, 1
+	if strings.HasPrefix(s, "#!") {
+		pos = hashbangEnd(s)
+	}
+
+	var directives []prologueDirective
+	for {
+		if pos, line, _ = skipTrivia(s, pos, line); pos >= len(s) || s[pos] != '\'' && s[pos] != '"' {
+			return directives
+		}
+		end := stringLiteralEnd(s, pos)
+		if end < 0 {
+			return directives
+		}
+		directive := prologueDirective{Text: s[pos:end], Line: line}
+		line += strings.Count(directive.Text, "\n")
+
+		next, nextLine, newline := skipTrivia(s, end, line)
+		switch {
+		case next >= len(s):
+			return append(directives, directive)
+		case s[next] == ';':
+			pos, line = next+1, nextLine
+		case newline && !strings.ContainsRune(".([`+-*/%,?=<>&|^", rune(s[next])):
+			pos, line = next, nextLine
+		default:
+			return directives
+		}
+		directives = append(directives, directive)
+	}
+}
+
+func hashbangEnd(s string) int {
+	if end := strings.IndexByte(s, '\n'); end >= 0 {
+		return end
+	}
+	return len(s)
+}
+
+func skipTrivia(s string, pos, line int) (int, int, bool) {
+	newline := false
+	for pos < len(s) {
+		switch {
+		case s[pos] == '\n':
+			newline = true
+			line++
+			pos++
+		case s[pos] == ' ' || s[pos] == '\t' || s[pos] == '\r' || s[pos] == '\f' || s[pos] == '\v':
+			pos++
+		case strings.HasPrefix(s[pos:], "//"):
+			end := strings.IndexByte(s[pos:], '\n')
+			if end < 0 {
+				return len(s), line, newline
+			}
+			pos += end
+		case strings.HasPrefix(s[pos:], "/*"):
+			end := strings.Index(s[pos+2:], "*/")
+			if end < 0 {
+				return len(s), line, newline
+			}
+			comment := s[pos : pos+2+end+2]
+			if lines := strings.Count(comment, "\n"); lines > 0 {
+				newline = true
+				line += lines
+			}
+			pos += len(comment)
+		default:
+			return pos, line, newline
+		}
+	}
+	return pos, line, newline
+}
+
+func stringLiteralEnd(s string, start int) int {
+	quote := s[start]
+	for pos := start + 1; pos < len(s); pos++ {
+		switch s[pos] {
+		case '\\':
+			if strings.HasPrefix(s[pos+1:], "\r\n") {
+				pos++
+			}
+			pos++
+		case '\n', '\r':
+			return -1
+		case quote:
+			return pos + 1
+		}
+	}
+	return -1
+}
+
+func hasDirective(r io.Reader, config *Config) bool {
+	content, err := io.ReadAll(io.LimitReader(r, config.MaxReadBytes))
+	if err != nil {
+		return false
+	}
+	for _, directive := range directivePrologue(content) {
+		if containsString(config.Directives, directive.Text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDirectivePrologue(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []prologueDirective
+	}{
+		{
+			name:    "single quotes",
+			content: "'use client'\nexport function A() {}\n",
+			want:    []prologueDirective{{Text: "'use client'", Line: 1}},
+		},
+		{
+			name:    "double quotes with semicolon",
+			content: "\"use client\";\n",
+			want:    []prologueDirective{{Text: `"use client"`, Line: 1}},
+		},
+		{
+			name:    "after a hashbang",
+			content: "#!/usr/bin/env node\n'use client'\n",
+			want:    []prologueDirective{{Text: "'use client'", Line: 2}},
+		},
+		{
+			name:    "after a byte order mark",
+			content: "\ufeff'use client'\n",
+			want:    []prologueDirective{{Text: "'use client'", Line: 1}},
+		},
+		{
+			name:    "after a multi-line pragma and a comment on the same line",
+			content: "/** @jsxImportSource\n  @emotion/react */ /* x */ 'use client' // trailing\nexport {}\n",
+			want:    []prologueDirective{{Text: "'use client'", Line: 2}},
+		},
+		{
+			name:    "after another directive",
+			content: "'use strict';\n\"use client\"\n",
+			want:    []prologueDirective{{Text: "'use strict'", Line: 1}, {Text: `"use client"`, Line: 2}},
+		},
+		{
+			name:    "CRLF line endings",
+			content: "#!/bin/node\r\n'use client';\r\nexport {}\r\n",
+			want:    []prologueDirective{{Text: "'use client'", Line: 2}},
+		},
+		{
+			name:    "backticks are a template literal",
+			content: "`use client`\n",
+		},
+		{
+			name:    "string used in an expression",
+			content: "'use client'.length\n",
+		},
+		{
+			name:    "string continued on the next line",
+			content: "'use client'\n+ 'x'\n",
+		},
+		{
+			name:    "after code",
+			content: "const x = 1\n'use client'\n",
+		},
+		{
+			name:    "unterminated string",
+			content: "'use client\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := directivePrologue([]byte(tt.content)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("directivePrologue() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasDirectiveReadLimit(t *testing.T) {
+	config := DefaultConfig()
+	license := "/*\n" + strings.Repeat(" * license text\n", 400) + " */\n"
+
+	if hasDirective(strings.NewReader(license+"'use client'\n"), config) {
+		t.Error("hasDirective() found a directive past MaxReadBytes")
+	}
+	if !hasDirective(strings.NewReader("/* short */\n'use client'\n"), config) {
+		t.Error("hasDirective() missed a directive after a comment")
+	}
+}
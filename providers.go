@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	childrenSlotRegex = regexp.MustCompile(`\{\s*(?:props\.)?children\s*\}`)
+	appComponentRegex = regexp.MustCompile(`<\s*Component\b`)
+	legacyAppRegex    = regexp.MustCompile(`^_app\.(tsx|jsx|ts|js)$`)
+)
+
+type ProviderStack struct {
+	Layout    string          `json:"layout"`
+	Route     string          `json:"route"`
+	Providers []ProviderUsage `json:"providers"`
+}
+
+type ProviderUsage struct {
+	Component string `json:"component"`
+	File      string `json:"file"`
+	Files     int    `json:"files"`
+	Bytes     int64  `json:"bytes"`
+}
+
+type jsxTag struct {
+	name        string
+	start, end  int
+	closing     bool
+	selfClosing bool
+}
+
+func runProvidersCommand(args []string) error {
+	command := newGraphCommand("providers")
+	config, graph, err := command.parse(args)
+	if err != nil {
+		return err
+	}
+
+	stacks := providerStacks(graph, config, *command.verbose)
+
+	return command.write(os.Stdout, stacks, func(w io.Writer) error {
+		return writeProvidersText(w, stacks)
+	})
+}
+
+func providerStacks(graph *ImportGraph, config *Config, verbose bool) []ProviderStack {
+	layouts := appRouterFiles(graph, layoutFileRegex)
+	for path, node := range graph.Nodes {
+		if node.loaded && legacyAppRegex.MatchString(filepath.Base(path)) && filepath.Base(filepath.Dir(path)) == "pages" {
+			layouts = append(layouts, path)
+		}
+	}
+	sortByDepth(layouts)
+
+	var stacks []ProviderStack
+	for _, layout := range layouts {
+		content, err := os.ReadFile(layout)
+		if err != nil {
+			continue
+		}
+		text := string(content)
+
+		var slot []int
+		route := "/"
+		if legacyAppRegex.MatchString(filepath.Base(layout)) {
+			slot = appComponentRegex.FindStringIndex(text)
+		} else {
+			route = routeForDir(filepath.Dir(layout))
+			for _, loc := range childrenSlotRegex.FindAllStringIndex(text, -1) {
+				if opensJSXContainer(text, loc[0]) {
+					slot = loc
+					break
+				}
+			}
+		}
+		if slot == nil {
+			continue
+		}
+
+		clientImports := make(map[string]string)
+		for _, edge := range graph.load(layout, config, verbose).Imports {
+			if graph.load(edge.Target, config, verbose).IsClient {
+				for _, spec := range edge.Specifiers {
+					clientImports[spec] = edge.Target
+				}
+			}
+		}
+
+		stack := ProviderStack{Layout: displayPath(layout), Route: route, Providers: []ProviderUsage{}}
+		for _, name := range jsxAncestors(text, slot[0]) {
+			target, ok := clientImports[name]
+			if !ok {
+				continue
+			}
+			usage := ProviderUsage{Component: name, File: displayPath(target)}
+			for _, node := range graph.closure(target, config, verbose) {
+				usage.Files++
+				usage.Bytes += node.Size
+			}
+			stack.Providers = append(stack.Providers, usage)
+		}
+		stacks = append(stacks, stack)
+	}
+
+	return stacks
+}
+
+func jsxAncestors(text string, offset int) []string {
+	var stack []string
+	for _, tag := range scanJSXTags(text[:offset]) {
+		switch {
+		case tag.selfClosing:
+		case tag.closing:
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] == tag.name {
+					stack = stack[:i]
+					break
+				}
+			}
+		default:
+			stack = append(stack, tag.name)
+		}
+	}
+	return stack
+}
+
+func scanJSXTags(text string) []jsxTag {
+	var tags []jsxTag
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '<' {
+			continue
+		}
+
+		j := i + 1
+		closing := j < len(text) && text[j] == '/'
+		if closing {
+			j++
+		}
+		nameStart := j
+		for j < len(text) && (isIdentByte(text[j]) || text[j] == '.') {
+			j++
+		}
+		name := text[nameStart:j]
+		if name == "" && !(j < len(text) && text[j] == '>') {
+			continue
+		}
+		if name != "" && !(name[0] >= 'A' && name[0] <= 'Z' || name[0] >= 'a' && name[0] <= 'z' || name[0] >= utf8.RuneSelf) {
+			continue
+		}
+
+		depth := 0
+		end := -1
+		for k := j; k < len(text); k++ {
+			switch text[k] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case '>':
+				if depth == 0 {
+					end = k
+				}
+			}
+			if end >= 0 {
+				break
+			}
+		}
+		if end < 0 {
+			break
+		}
+
+		tags = append(tags, jsxTag{
+			name:        name,
+			start:       i,
+			end:         end,
+			closing:     closing,
+			selfClosing: strings.HasSuffix(strings.TrimSpace(text[j:end]), "/"),
+		})
+		i = end
+	}
+
+	return tags
+}
+
+func writeProvidersText(w io.Writer, stacks []ProviderStack) error {
+	for _, stack := range stacks {
+		fmt.Fprintf(w, "%s (%s)\n", stack.Layout, stack.Route)
+		if len(stack.Providers) == 0 {
+			fmt.Fprintln(w, "  no client providers")
+		}
+		for i, provider := range stack.Providers {
+			fmt.Fprintf(w, "  %d. %s  %s  %d files, %d bytes\n", i+1, provider.Component, provider.File, provider.Files, provider.Bytes)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+var routeEntryRegex = regexp.MustCompile(`^(page|layout|template|loading|error|global-error|not-found|default)\.(tsx|jsx|ts|js|mdx)$`)
+
+type RedundantDirective struct {
+	File      string   `json:"file"`
+	Reason    string   `json:"reason"`
+	Importers []string `json:"importers"`
+}
+
+func runRedundantCommand(args []string) error {
+	command := newGraphCommand("redundant")
+	config, graph, err := command.parse(args)
+	if err != nil {
+		return err
+	}
+
+	redundant := findRedundantDirectives(graph, config, *command.verbose)
+
+	return command.write(os.Stdout, redundant, func(w io.Writer) error {
+		return writeRedundantText(w, redundant)
+	})
+}
+
+func findRedundantDirectives(graph *ImportGraph, config *Config, verbose bool) []RedundantDirective {
+	var paths []string
+	for path, node := range graph.Nodes {
+		if node.loaded {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	edges := make(map[string][]string)
+	importers := make(map[string][]string)
+	for _, path := range paths {
+		for _, target := range moduleEdges(graph.Nodes[path], config) {
+			edges[path] = append(edges[path], target)
+			importers[target] = appendUnique(importers[target], path)
+		}
+	}
+
+	needed := make(map[string]bool)
+	visited := make(map[string]bool)
+	var queue []string
+	for _, path := range paths {
+		node := graph.Nodes[path]
+		switch {
+		case node.IsClient && isRouteEntry(path):
+			needed[path] = true
+		case !node.IsClient && len(importers[path]) == 0:
+			visited[path] = true
+			queue = append(queue, path)
+		}
+	}
+
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		for _, target := range edges[path] {
+			if graph.load(target, config, verbose).IsClient {
+				needed[target] = true
+				continue
+			}
+			if !visited[target] {
+				visited[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	redundant := []RedundantDirective{}
+	for _, path := range paths {
+		if !graph.Nodes[path].IsClient || needed[path] {
+			continue
+		}
+
+		entry := RedundantDirective{File: displayPath(path), Reason: "never imported", Importers: []string{}}
+		if len(importers[path]) > 0 {
+			entry.Reason = "only imported from client components"
+			for _, importer := range importers[path] {
+				entry.Importers = append(entry.Importers, displayPath(importer))
+			}
+		}
+		redundant = append(redundant, entry)
+	}
+	return redundant
+}
+
+func moduleEdges(node *GraphNode, config *Config) []string {
+	var targets []string
+	for _, edge := range node.Imports {
+		targets = appendUnique(targets, edge.Target)
+	}
+
+	if table := loadExportTable(node.Path, config); table != nil {
+		var sources []string
+		for _, named := range table.Named {
+			sources = append(sources, named.Source)
+		}
+		sources = append(sources, table.All...)
+		sort.Strings(sources)
+		for _, source := range sources {
+			for _, path := range resolveFrom(node.Path, source, config) {
+				targets = appendUnique(targets, absPath(path))
+			}
+		}
+	}
+
+	if content, err := os.ReadFile(node.Path); err == nil {
+		for _, imp := range parseLazyImports(splitLines(string(content))) {
+			for _, path := range resolveFrom(node.Path, imp.Source, config) {
+				targets = appendUnique(targets, absPath(path))
+			}
+		}
+	}
+	return targets
+}
+
+func isRouteEntry(path string) bool {
+	return routeEntryRegex.MatchString(filepath.Base(path)) && appDir(path) != ""
+}
+
+func appendUnique(items []string, item string) []string {
+	if containsString(items, item) {
+		return items
+	}
+	return append(items, item)
+}
+
+func writeRedundantText(w io.Writer, redundant []RedundantDirective) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tREASON\tIMPORTERS")
+	for _, entry := range redundant {
+		importers := strings.Join(entry.Importers, ", ")
+		if importers == "" {
+			importers = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", entry.File, entry.Reason, importers)
+	}
+	return tw.Flush()
+}
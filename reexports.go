@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+var (
+	reexportNamedRegex = regexp.MustCompile(`(?m)^\s*export\s*\{([^}]*)\}\s*from\s*['"]([^'"]+)['"]`)
+	reexportAllRegex   = regexp.MustCompile(`(?m)^\s*export\s*\*\s*from\s*['"]([^'"]+)['"]`)
+	localExportRegex   = regexp.MustCompile(`(?m)^\s*export\s+(?:async\s+)?(?:function\s*\*?|const|let|var|class)\s+(` + identPattern + `)`)
+	defaultExportRegex = regexp.MustCompile(`(?m)^\s*export\s+default\b`)
+	defaultIdentRegex  = regexp.MustCompile(`(?m)^\s*export\s+default\s+(` + identPattern + `)\s*;?\s*$`)
+)
+
+type reexport struct {
+	Name   string
+	Source string
+}
+
+type exportTable struct {
+	Named map[string]reexport
+	All   []string
+	Local map[string]bool
+	Async map[string]bool
+
+	Imports []string
+
+	modTime int64
+	size    int64
+}
+
+type exportMemo struct {
+	mu        sync.Mutex
+	tables    map[string]*exportTable
+	diagnosed map[string]bool
+}
+
+func newExportMemo() *exportMemo {
+	return &exportMemo{tables: make(map[string]*exportTable), diagnosed: make(map[string]bool)}
+}
+
+func (m *exportMemo) diagnose(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if m != nil {
+		m.mu.Lock()
+		seen := m.diagnosed[message]
+		m.diagnosed[message] = true
+		m.mu.Unlock()
+		if seen {
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", message)
+}
+
+func parseExportTable(content string) *exportTable {
+	table := &exportTable{Named: make(map[string]reexport), Local: make(map[string]bool), Async: asyncExports(content)}
+
+	for _, match := range reexportNamedRegex.FindAllStringSubmatch(content, -1) {
+		exported := parseNamedSpecifiers(match[1])
+		for i, name := range parseImportedNames(match[1]) {
+			if i < len(exported) {
+				table.Named[exported[i]] = reexport{Name: name, Source: match[2]}
+			}
+		}
+	}
+
+	for _, match := range reexportAllRegex.FindAllStringSubmatch(content, -1) {
+		table.All = append(table.All, match[1])
+	}
+
+	bindings := make(map[string]reexport)
+	for _, imp := range parseImports(splitLines(content)) {
+		table.Imports = append(table.Imports, imp.Source)
+		for i, local := range imp.Specifiers {
+			if i < len(imp.Names) && imp.Names[i] != "*" {
+				bindings[local] = reexport{Name: imp.Names[i], Source: imp.Source}
+			}
+		}
+	}
+
+	for _, match := range localExportRegex.FindAllStringSubmatch(content, -1) {
+		table.Local[match[1]] = true
+	}
+	for _, match := range exportListRegex.FindAllStringSubmatch(content, -1) {
+		locals := parseImportedNames(match[1])
+		for i, exported := range parseNamedSpecifiers(match[1]) {
+			if i < len(locals) && bindings[locals[i]].Source != "" {
+				table.Named[exported] = bindings[locals[i]]
+				continue
+			}
+			table.Local[exported] = true
+		}
+	}
+	if match := defaultIdentRegex.FindStringSubmatch(content); match != nil && bindings[match[1]].Source != "" {
+		table.Named["default"] = bindings[match[1]]
+	} else if defaultExportRegex.MatchString(content) {
+		table.Local["default"] = true
+	}
+
+	return table
+}
+
+func loadExportTable(path string, config *Config) *exportTable {
+	config.dependsOn(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	if config.exports != nil {
+		config.exports.mu.Lock()
+		table, ok := config.exports.tables[path]
+		config.exports.mu.Unlock()
+		if ok && table.modTime == info.ModTime().UnixNano() && table.size == info.Size() {
+			return table
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	table := parseExportTable(string(content))
+	table.modTime = info.ModTime().UnixNano()
+	table.size = info.Size()
+
+	if config.exports != nil {
+		config.exports.mu.Lock()
+		config.exports.tables[path] = table
+		config.exports.mu.Unlock()
+	}
+	return table
+}
+
+func resolveReexport(path, name string, config *Config) string {
+	defining, _ := resolveReexportName(path, name, config)
+	return defining
+}
+
+func resolveReexportName(path, name string, config *Config) (string, string) {
+	quiet := *config
+	quiet.ExplainResolution = false
+	quiet.TraceAliases = false
+
+	if defining, local, ok := findExport(path, name, &quiet, config, nil); ok {
+		return defining, local
+	}
+	return path, name
+}
+
+func findExport(path, name string, quiet, config *Config, chain []reexport) (string, string, bool) {
+	for i, link := range chain {
+		if link.Source == path && link.Name == name {
+			if config.Limits.Cycles == CyclesWarn {
+				config.exports.diagnose("re-export cycle for '%s': %s", name, formatReexportChain(append(chain[i:], link)))
+			}
+			return "", "", false
+		}
+	}
+	if len(chain) > config.Limits.MaxReexportHops {
+		config.exports.diagnose("stopped following '%s' at %s (maxReexportHops %d): %s", name, displayPath(path), config.Limits.MaxReexportHops, formatReexportChain(chain))
+		return "", "", false
+	}
+	chain = append(chain[:len(chain):len(chain)], reexport{Name: name, Source: path})
+
+	table := loadExportTable(path, config)
+	if table == nil {
+		return "", "", false
+	}
+	if table.Local[name] {
+		return path, name, true
+	}
+
+	if named, ok := table.Named[name]; ok {
+		next := resolveFrom(path, named.Source, quiet)
+		if len(next) == 0 {
+			return "", "", false
+		}
+		explainf(config, "  %s re-exports '%s' from %s", path, name, next[0])
+		if defining, local, ok := findExport(next[0], named.Name, quiet, config, chain); ok {
+			return defining, local, true
+		}
+		return next[0], named.Name, true
+	}
+
+	if name == "default" {
+		return "", "", false
+	}
+	for _, source := range table.All {
+		next := resolveFrom(path, source, quiet)
+		if len(next) == 0 {
+			continue
+		}
+		if defining, local, ok := findExport(next[0], name, quiet, config, chain); ok {
+			explainf(config, "  %s re-exports '%s' from %s via export *", path, name, next[0])
+			return defining, local, true
+		}
+	}
+	return "", "", false
+}
+
+func formatReexportChain(chain []reexport) string {
+	paths := make([]string, len(chain))
+	for i, link := range chain {
+		paths[i] = link.Source
+	}
+	return formatCycle(paths)
+}
+
+func namespaceMembers(content []byte, namespace string) []string {
+	pattern := regexp.MustCompile(`(?:<|\bcreateElement\s*\(|[\[,])\s*` + regexp.QuoteMeta(namespace) + `\.(` + identPattern + `)`)
+
+	seen := make(map[string]bool)
+	var members []string
+	for _, match := range pattern.FindAllSubmatch(content, -1) {
+		if member := string(match[1]); !seen[member] {
+			seen[member] = true
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+func resolveFrom(filePath, source string, config *Config) []string {
+	baseDir := filepath.Dir(filePath)
+	project, _ := loadProjectConfig(filePath, config)
+	importMap, _ := loadImportMap(baseDir, config)
+	return resolveImportPath(baseDir, source, project, importMap, config).Paths
+}
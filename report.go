@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/conao3/go-rsc-boundary/rscboundary"
+)
+
+const maxUnresolvedExamples = 10
+
+type reportTarget struct {
+	Format string
+	Path   string
+}
+
+type reportTargets []reportTarget
+
+func (r *reportTargets) String() string {
+	var targets []string
+	for _, target := range *r {
+		targets = append(targets, target.Format+"="+target.Path)
+	}
+	return strings.Join(targets, ",")
+}
+
+func (r *reportTargets) Set(value string) error {
+	format, path, ok := strings.Cut(value, "=")
+	if !ok || path == "" {
+		return fmt.Errorf("expected format=file, got %q", value)
+	}
+	if !containsString(rscboundary.Formats(), format) {
+		return fmt.Errorf("unknown format: %s", format)
+	}
+	*r = append(*r, reportTarget{Format: format, Path: path})
+	return nil
+}
+
+func writeReportFile(target reportTarget, result *ScanResult) error {
+	file, err := os.Create(target.Path)
+	if err != nil {
+		return err
+	}
+	if err := writeReport(file, target.Format, result); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+type jsonReport struct {
+	Findings   []Finding        `json:"findings"`
+	Unresolved unresolvedReport `json:"unresolved"`
+	Suppressed suppressedReport `json:"suppressed"`
+}
+
+type suppressedReport struct {
+	Count int            `json:"count"`
+	Rules map[string]int `json:"rules"`
+}
+
+type unresolvedReport struct {
+	Count    int                `json:"count"`
+	Examples []UnresolvedImport `json:"examples"`
+}
+
+func writeReport(w io.Writer, format string, result *ScanResult) error {
+	reporter, err := rscboundary.NewReporter(format)
+	if err != nil {
+		return err
+	}
+	if err := reporter.Start(w); err != nil {
+		return err
+	}
+	for _, f := range result.Findings {
+		if err := reporter.Report(f); err != nil {
+			return err
+		}
+	}
+	return reporter.Finish(result)
+}
+
+func slashPaths(r *ScanResult) {
+	for i := range r.Findings {
+		r.Findings[i].File = filepath.ToSlash(r.Findings[i].File)
+		r.Findings[i].ResolvedClientFile = filepath.ToSlash(r.Findings[i].ResolvedClientFile)
+	}
+	for i := range r.Unresolved {
+		r.Unresolved[i].File = filepath.ToSlash(r.Unresolved[i].File)
+		for j := range r.Unresolved[i].Tried {
+			r.Unresolved[i].Tried[j] = filepath.ToSlash(r.Unresolved[i].Tried[j])
+		}
+	}
+}
+
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+func printFinding(w io.Writer, f Finding) {
+	position := fmt.Sprintf("%s:%d", f.File, f.Line)
+	if f.Column > 0 {
+		position += fmt.Sprintf(":%d", f.Column)
+	}
+
+	suffix := ""
+	if f.Message != "" {
+		suffix += fmt.Sprintf(" [%s]", f.Rule)
+	}
+	if f.Route != "" {
+		suffix += fmt.Sprintf(" [route %s]", f.Route)
+	}
+	if f.ClientSubtree != nil {
+		suffix += fmt.Sprintf(" [client subtree: %d files, %d lines, %d bytes]", f.ClientSubtree.Files, f.ClientSubtree.Lines, f.ClientSubtree.Bytes)
+	}
+
+	if f.Message == "" {
+		fmt.Fprintf(w, "%s:%s%s\n", position, f.Content, suffix)
+		return
+	}
+	fmt.Fprintf(w, "%s:%s: %s%s\n", position, f.Severity, f.Message, suffix)
+}
+
+func printGitHubAnnotation(w io.Writer, f Finding) {
+	command := "warning"
+	if f.Severity == SeverityError {
+		command = "error"
+	}
+
+	properties := fmt.Sprintf("file=%s,line=%d", githubProperty(filepath.ToSlash(f.File)), f.Line)
+	if f.Column > 0 {
+		properties += fmt.Sprintf(",col=%d", f.Column)
+	}
+	properties += ",title=" + githubProperty("rsc-boundary "+f.Rule)
+
+	fmt.Fprintf(w, "::%s %s::%s\n", command, properties, githubData(findingMessage(f)))
+}
+
+func githubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func githubProperty(s string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(githubData(s))
+}
+
+func writeDelimitedReport(w io.Writer, comma rune, result *ScanResult) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	writer.Write([]string{"file", "line", "component", "clientFile", "importSource"})
+	for _, f := range result.Findings {
+		writer.Write([]string{filepath.ToSlash(f.File), strconv.Itoa(f.Line), f.Component, filepath.ToSlash(f.ResolvedClientFile), f.ImportSource})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeJSONReport(w io.Writer, result *ScanResult) error {
+	report := jsonReport{
+		Findings: result.Findings,
+		Unresolved: unresolvedReport{
+			Count:    len(result.Unresolved),
+			Examples: result.Unresolved,
+		},
+		Suppressed: suppressedReport{
+			Count: result.SuppressedCount(),
+			Rules: result.Suppressed,
+		},
+	}
+	if report.Findings == nil {
+		report.Findings = []Finding{}
+	}
+	if len(report.Unresolved.Examples) > maxUnresolvedExamples {
+		report.Unresolved.Examples = report.Unresolved.Examples[:maxUnresolvedExamples]
+	}
+	if report.Suppressed.Rules == nil {
+		report.Suppressed.Rules = map[string]int{}
+	}
+	if report.Unresolved.Examples == nil {
+		report.Unresolved.Examples = []UnresolvedImport{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(report)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"io"
+
+	"github.com/conao3/go-rsc-boundary/rscboundary"
+)
+
+func init() {
+	rscboundary.RegisterReporter("grep", rscboundary.LineFormat(printFinding))
+	rscboundary.RegisterReporter("pretty", rscboundary.DocumentFormat(writePrettyReport))
+	rscboundary.RegisterReporter("json", rscboundary.DocumentFormat(writeJSONReport))
+	rscboundary.RegisterReporter("sarif", rscboundary.DocumentFormat(writeSARIFReport))
+	rscboundary.RegisterReporter("github", rscboundary.LineFormat(printGitHubAnnotation))
+	rscboundary.RegisterReporter("html", rscboundary.DocumentFormat(writeHTMLReport))
+	rscboundary.RegisterReporter("checkstyle", rscboundary.DocumentFormat(writeCheckstyleReport))
+	rscboundary.RegisterReporter("junit", rscboundary.DocumentFormat(writeJUnitReport))
+	rscboundary.RegisterReporter("csv", rscboundary.DocumentFormat(func(w io.Writer, result *ScanResult) error {
+		return writeDelimitedReport(w, ',', result)
+	}))
+	rscboundary.RegisterReporter("tsv", rscboundary.DocumentFormat(func(w io.Writer, result *ScanResult) error {
+		return writeDelimitedReport(w, '\t', result)
+	}))
+}
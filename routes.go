@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"text/tabwriter"
+)
+
+var segmentFileRegex = regexp.MustCompile(`^(layout|template|loading|error|not-found)\.(tsx|jsx|ts|js|mdx)$`)
+
+type RouteReport struct {
+	Route            string    `json:"route"`
+	Page             string    `json:"page"`
+	Segments         []string  `json:"segments"`
+	ClientComponents []string  `json:"clientComponents"`
+	ClientBytes      int64     `json:"clientBytes"`
+	Findings         []Finding `json:"findings"`
+}
+
+func runRoutesCommand(args []string) error {
+	command := newGraphCommand("routes")
+	config, graph, err := command.parse(args)
+	if err != nil {
+		return err
+	}
+
+	routes := routeReports(graph, config, *command.verbose)
+
+	return command.write(os.Stdout, routes, func(w io.Writer) error {
+		return writeRoutesText(w, routes)
+	})
+}
+
+func routeReports(graph *ImportGraph, config *Config, verbose bool) []RouteReport {
+	scanned := make(map[string][]Finding)
+	findings := func(path string) []Finding {
+		if cached, ok := scanned[path]; ok {
+			return cached
+		}
+		result := &ScanResult{}
+		if err := scanFile(displayPath(path), config, verbose, result); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to scan %s: %v\n", path, err)
+		}
+		scanned[path] = result.Findings
+		return result.Findings
+	}
+
+	var routes []RouteReport
+	for _, page := range appRouterFiles(graph, pageFileRegex) {
+		dir := filepath.Dir(page)
+		report := RouteReport{
+			Route:            routeForDir(dir),
+			Page:             displayPath(page),
+			Segments:         []string{},
+			ClientComponents: []string{},
+			Findings:         []Finding{},
+		}
+
+		entries := []string{page}
+		root := appDir(page)
+		for segment := dir; isWithin(segment, root); segment = filepath.Dir(segment) {
+			entries = append(entries, segmentFiles(graph, segment)...)
+			if segment == root {
+				break
+			}
+		}
+		for _, entry := range entries[1:] {
+			report.Segments = append(report.Segments, displayPath(entry))
+		}
+
+		servers, clients := routeModules(graph, entries, config, verbose)
+		closure := make(map[string]int64)
+		for _, client := range clients {
+			report.ClientComponents = append(report.ClientComponents, displayPath(client))
+			for _, n := range graph.closure(client, config, verbose) {
+				closure[n.Path] = n.Size
+			}
+		}
+		report.ClientBytes = sumSizes(closure)
+
+		for _, server := range servers {
+			report.Findings = append(report.Findings, findings(server)...)
+		}
+		for i := range report.Findings {
+			report.Findings[i].Route = report.Route
+		}
+		routes = append(routes, report)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Route < routes[j].Route
+	})
+	return routes
+}
+
+func segmentFiles(graph *ImportGraph, dir string) []string {
+	var files []string
+	for path, node := range graph.Nodes {
+		if node.loaded && filepath.Dir(path) == dir && segmentFileRegex.MatchString(filepath.Base(path)) {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func routeModules(graph *ImportGraph, entries []string, config *Config, verbose bool) ([]string, []string) {
+	var servers, clients []string
+	visited := make(map[string]bool)
+	queue := append([]string{}, entries...)
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if visited[path] {
+			continue
+		}
+		visited[path] = true
+
+		node := graph.load(path, config, verbose)
+		if node.IsClient {
+			clients = append(clients, path)
+			continue
+		}
+		servers = append(servers, path)
+		queue = append(queue, moduleEdges(node, config)...)
+	}
+
+	sort.Strings(servers)
+	sort.Strings(clients)
+	return servers, clients
+}
+
+func writeRoutesText(w io.Writer, routes []RouteReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROUTE\tPAGE\tCLIENT COMPONENTS\tCLIENT BYTES\tFINDINGS")
+	for _, route := range routes {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\n", route.Route, route.Page, len(route.ClientComponents), route.ClientBytes, len(route.Findings))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,97 @@
+package rscboundary
+
+import (
+	"fmt"
+	"io"
+)
+
+// Reporter writes findings in one output format. Finish receives the whole
+// result for unresolved imports and suppression counts.
+type Reporter interface {
+	Start(w io.Writer) error
+	Report(f Finding) error
+	Finish(result *ScanResult) error
+}
+
+var (
+	reporters = make(map[string]func() Reporter)
+	formats   []string
+)
+
+// RegisterReporter makes a reporter available to -format and -report.
+// Registering an existing name replaces its factory.
+func RegisterReporter(name string, factory func() Reporter) {
+	if _, ok := reporters[name]; !ok {
+		formats = append(formats, name)
+	}
+	reporters[name] = factory
+}
+
+// Formats lists the registered format names in registration order.
+func Formats() []string {
+	return append([]string(nil), formats...)
+}
+
+// NewReporter returns a fresh reporter for a registered format.
+func NewReporter(format string) (Reporter, error) {
+	factory, ok := reporters[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+	return factory(), nil
+}
+
+// LineFormat adapts a function printing one finding at a time.
+func LineFormat(print func(io.Writer, Finding)) func() Reporter {
+	return func() Reporter {
+		return &lineReporter{print: print}
+	}
+}
+
+// DocumentFormat adapts a function writing the complete result at the end.
+func DocumentFormat(write func(io.Writer, *ScanResult) error) func() Reporter {
+	return func() Reporter {
+		return &documentReporter{write: write}
+	}
+}
+
+type lineReporter struct {
+	w     io.Writer
+	print func(io.Writer, Finding)
+}
+
+func (r *lineReporter) Start(w io.Writer) error {
+	r.w = w
+	return nil
+}
+
+func (r *lineReporter) Report(f Finding) error {
+	r.print(r.w, f)
+	return nil
+}
+
+func (r *lineReporter) Finish(result *ScanResult) error {
+	return nil
+}
+
+type documentReporter struct {
+	w        io.Writer
+	findings []Finding
+	write    func(io.Writer, *ScanResult) error
+}
+
+func (r *documentReporter) Start(w io.Writer) error {
+	r.w = w
+	return nil
+}
+
+func (r *documentReporter) Report(f Finding) error {
+	r.findings = append(r.findings, f)
+	return nil
+}
+
+func (r *documentReporter) Finish(result *ScanResult) error {
+	document := *result
+	document.Findings = r.findings
+	return r.write(r.w, &document)
+}
@@ -0,0 +1,75 @@
+// Package rscboundary holds the scan result types of go-rsc-boundary and the
+// reporter registry behind -format and -report, so output sinks can be added
+// without forking the tool.
+package rscboundary
+
+// Finding is one reported problem at a file position. EndColumn, when set,
+// is the column just past the matched text. InClientFile marks client
+// component usages in a file that itself has a client directive.
+type Finding struct {
+	File               string       `json:"file"`
+	Line               int          `json:"line"`
+	Column             int          `json:"column,omitempty"`
+	EndColumn          int          `json:"endColumn,omitempty"`
+	Content            string       `json:"content"`
+	Rule               string       `json:"rule"`
+	Severity           string       `json:"severity"`
+	Message            string       `json:"message,omitempty"`
+	Component          string       `json:"component,omitempty"`
+	ImportSource       string       `json:"importSource,omitempty"`
+	ResolvedClientFile string       `json:"resolvedClientFile,omitempty"`
+	InClientFile       bool         `json:"inClientFile,omitempty"`
+	ClientSubtree      *SubtreeSize `json:"clientSubtree,omitempty"`
+	Route              string       `json:"route,omitempty"`
+	Fingerprint        string       `json:"fingerprint,omitempty"`
+}
+
+// SubtreeSize is the code a client component pulls into the client bundle.
+type SubtreeSize struct {
+	Files int   `json:"files"`
+	Lines int   `json:"lines"`
+	Bytes int64 `json:"bytes"`
+}
+
+// UnresolvedImport is an import whose target could not be found.
+type UnresolvedImport struct {
+	File   string   `json:"file"`
+	Line   int      `json:"line"`
+	Source string   `json:"source"`
+	Tried  []string `json:"tried"`
+}
+
+// ScanResult is the outcome of scanning one or more files. Resolved and
+// Scanned are bookkeeping for the scanner and are not serialized.
+type ScanResult struct {
+	Findings   []Finding          `json:"findings"`
+	Unresolved []UnresolvedImport `json:"unresolved"`
+	Suppressed map[string]int     `json:"suppressed,omitempty"`
+
+	Resolved []string `json:"-"`
+	Scanned  []string `json:"-"`
+}
+
+// Merge appends the findings, unresolved imports and suppression counts of
+// other to r.
+func (r *ScanResult) Merge(other *ScanResult) {
+	r.Findings = append(r.Findings, other.Findings...)
+	r.Unresolved = append(r.Unresolved, other.Unresolved...)
+	r.Resolved = append(r.Resolved, other.Resolved...)
+	r.Scanned = append(r.Scanned, other.Scanned...)
+	for rule, count := range other.Suppressed {
+		if r.Suppressed == nil {
+			r.Suppressed = make(map[string]int)
+		}
+		r.Suppressed[rule] += count
+	}
+}
+
+// SuppressedCount is the number of findings silenced by suppression comments.
+func (r *ScanResult) SuppressedCount() int {
+	count := 0
+	for _, n := range r.Suppressed {
+		count += n
+	}
+	return count
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+var ruleDescriptions = []struct {
+	ID          string
+	Description string
+}{
+	{RuleClientUsage, "Client component rendered from another file"},
+	{RuleUnresolvedImport, "Local import that does not resolve to a file"},
+	{RuleAmbiguousImport, "Import resolving to files with different directives"},
+	{RuleClientInLoop, "Client component rendered inside a loop in a server file"},
+	{RuleServerAction, "Server action imported, invoked or passed from a server file to client code"},
+	{RuleMissingClientDirective, "Server file using client-only hooks or event handlers"},
+	{RuleServerOnlyImport, "Client file importing server-only code or a Node built-in"},
+	{RuleClientOnlyImport, "Server file importing client-only code"},
+	{RuleAsyncClientComponent, "Async component rendered from a client file"},
+	{RuleBrowserAPI, "Server file using browser globals or ReactDOM.createPortal"},
+	{RuleImportCasing, "Import whose casing differs from the file on disk"},
+	{RuleNonSerializableProp, "Function, class instance, Date or Symbol passed as a prop to a client component"},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+func writeSARIFReport(w io.Writer, result *ScanResult) error {
+	driver := sarifDriver{
+		Name:           "go-rsc-boundary",
+		InformationURI: "https://github.com/conao3/go-rsc-boundary",
+	}
+	ruleIndex := make(map[string]int)
+	for i, rule := range ruleDescriptions {
+		driver.Rules = append(driver.Rules, sarifRule{ID: rule.ID, ShortDescription: sarifMessage{Text: rule.Description}})
+		ruleIndex[rule.ID] = i
+	}
+
+	results := []sarifResult{}
+	for _, f := range result.Findings {
+		if _, ok := ruleIndex[f.Rule]; !ok {
+			ruleIndex[f.Rule] = len(driver.Rules)
+			driver.Rules = append(driver.Rules, sarifRule{ID: f.Rule, ShortDescription: sarifMessage{Text: fmt.Sprintf("Component from the %s boundary rendered outside it", f.Rule)}})
+		}
+		sarif := sarifResult{
+			RuleID:    f.Rule,
+			RuleIndex: ruleIndex[f.Rule],
+			Level:     f.Severity,
+			Message:   sarifMessage{Text: findingMessage(f)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(displayPath(f.File)), URIBaseID: "%SRCROOT%"},
+					Region:           sarifRegion{StartLine: f.Line, StartColumn: f.Column, EndColumn: f.EndColumn},
+				},
+			}},
+		}
+		if f.Fingerprint != "" {
+			sarif.PartialFingerprints = map[string]string{"rscBoundary/v1": f.Fingerprint}
+		}
+		results = append(results, sarif)
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(log)
+}
+
+func findingMessage(f Finding) string {
+	if f.Message != "" {
+		return f.Message
+	}
+	importer := "a server file"
+	if f.InClientFile {
+		importer = "a client file"
+	}
+	if f.Component != "" {
+		return fmt.Sprintf("client component <%s> from '%s' rendered in %s", f.Component, f.ImportSource, importer)
+	}
+	return "client component rendered in " + importer
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func scanFixture(t *testing.T, root string, names ...string) *ScanResult {
+	t.Helper()
+	config := DefaultConfig()
+	result := &ScanResult{}
+	for _, name := range names {
+		if err := scanFile(filepath.Join(root, filepath.FromSlash(name)), config, false, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return result
+}
+
+func TestFindingMessage(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"B.tsx":    "'use client'\nexport function B() { return null }\n",
+		"C.tsx":    "'use client'\nimport { B } from './B'\nexport function C() { return <B /> }\n",
+		"page.tsx": "import { B } from './B'\nexport default function P() { return <B /> }\n",
+	})
+	result := scanFixture(t, root, "C.tsx", "page.tsx")
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"C.tsx", "client component <B> from './B' rendered in a client file"},
+		{"page.tsx", "client component <B> from './B' rendered in a server file"},
+	}
+	for i, tt := range tests {
+		f := result.Findings[i]
+		if filepath.Base(f.File) != tt.file {
+			t.Fatalf("finding %d is in %s, want %s", i, f.File, tt.file)
+		}
+		if got := findingMessage(f); got != tt.want {
+			t.Errorf("findingMessage() for %s = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestWriteSARIFReport(t *testing.T) {
+	result := &ScanResult{Findings: []Finding{
+		{File: "app/page.tsx", Line: 3, Column: 7, Rule: RuleClientUsage, Severity: SeverityWarning, Component: "Button", ImportSource: "./Button", Fingerprint: "abc"},
+		{File: "app/page.tsx", Line: 1, Rule: RuleUnresolvedImport, Severity: SeverityError, Message: "unresolved import './x'"},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeSARIFReport(&buf, result); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("SARIF log version %q with %d runs, want 2.1.0 with one run", log.Version, len(log.Runs))
+	}
+
+	run := log.Runs[0]
+	tests := []struct {
+		ruleID  string
+		level   string
+		message string
+		column  int
+	}{
+		{RuleClientUsage, "warning", "client component <Button> from './Button' rendered in a server file", 7},
+		{RuleUnresolvedImport, "error", "unresolved import './x'", 0},
+	}
+	if len(run.Results) != len(tests) {
+		t.Fatalf("got %d results, want %d", len(run.Results), len(tests))
+	}
+	for i, tt := range tests {
+		r := run.Results[i]
+		if r.RuleID != tt.ruleID || r.Level != tt.level || r.Message.Text != tt.message {
+			t.Errorf("result %d = %s/%s/%q, want %s/%s/%q", i, r.RuleID, r.Level, r.Message.Text, tt.ruleID, tt.level, tt.message)
+		}
+		if run.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+			t.Errorf("result %d has ruleIndex %d pointing at %s", i, r.RuleIndex, run.Tool.Driver.Rules[r.RuleIndex].ID)
+		}
+		region := r.Locations[0].PhysicalLocation.Region
+		if region.StartColumn != tt.column {
+			t.Errorf("result %d startColumn = %d, want %d", i, region.StartColumn, tt.column)
+		}
+	}
+	if got := run.Results[0].PartialFingerprints["rscBoundary/v1"]; got != "abc" {
+		t.Errorf("partial fingerprint = %q, want %q", got, "abc")
+	}
+}
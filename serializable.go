@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+func findNonSerializableProps(filePath, text string, lines []string, components map[string]clientImport) []Finding {
+	tokens := tokenize(text)
+	starts := make(map[int]int, len(tokens))
+	for i, t := range tokens {
+		starts[t.offset] = i
+	}
+
+	names := make([]string, 0, len(components))
+	for component := range components {
+		names = append(names, component)
+	}
+	sort.Strings(names)
+
+	var findings []Finding
+	for _, component := range names {
+		tagRegex := regexp.MustCompile(jsxTagPattern(component))
+		for _, loc := range tagRegex.FindAllStringIndex(text, -1) {
+			start, ok := starts[loc[0]]
+			if !ok {
+				continue
+			}
+
+			for _, prop := range jsxProps(tokens, start) {
+				kind := nonSerializableKind(prop.value)
+				if kind == "" {
+					continue
+				}
+
+				name := tokens[prop.name]
+				findings = append(findings, Finding{
+					File:               filePath,
+					Line:               name.line,
+					Column:             name.offset - strings.LastIndex(text[:name.offset], "\n"),
+					Content:            lines[name.line-1],
+					Rule:               RuleNonSerializableProp,
+					Severity:           SeverityWarning,
+					Message:            fmt.Sprintf("prop '%s' passed to client component <%s> is %s, which cannot be serialized", name.text, component, kind),
+					Component:          component,
+					ImportSource:       components[component].Source,
+					ResolvedClientFile: components[component].Resolved,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	return findings
+}
+
+type jsxProp struct {
+	name  int
+	value []token
+}
+
+func jsxProps(tokens []token, start int) []jsxProp {
+	i := start + 1
+	if i >= len(tokens) || tokens[i].kind != tokenIdent {
+		return nil
+	}
+	i++
+	for i+1 < len(tokens) && tokens[i].text == "." && tokens[i+1].kind == tokenIdent {
+		i += 2
+	}
+
+	var props []jsxProp
+	for i < len(tokens) {
+		t := tokens[i]
+		switch {
+		case t.text == "{":
+			i = matchingBrace(tokens, i) + 1
+			continue
+		case t.kind != tokenIdent:
+			return props
+		}
+
+		name := i
+		i++
+		for i+1 < len(tokens) && (tokens[i].text == "-" || tokens[i].text == ":") && tokens[i+1].kind == tokenIdent {
+			i += 2
+		}
+		if tokenText(tokens, i) != "=" {
+			continue
+		}
+		i++
+
+		if tokenText(tokens, i) != "{" {
+			i++
+			continue
+		}
+		end := matchingBrace(tokens, i)
+		props = append(props, jsxProp{name: name, value: tokens[i+1 : end]})
+		i = end + 1
+	}
+	return props
+}
+
+func matchingBrace(tokens []token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+func nonSerializableKind(expr []token) string {
+	if len(expr) == 0 {
+		return ""
+	}
+
+	start := 0
+	if expr[0].text == "async" {
+		start = 1
+	}
+	switch tokenText(expr, start) {
+	case "function":
+		if isServerActionBody(expr, start) {
+			return ""
+		}
+		return "an inline function"
+	case "new":
+		if tokenText(expr, start+1) == "Date" {
+			return "a Date"
+		}
+		return fmt.Sprintf("a class instance (new %s)", tokenText(expr, start+1))
+	case "Symbol":
+		return "a Symbol"
+	}
+
+	arrow := -1
+	switch {
+	case expr[start].kind == tokenIdent:
+		arrow = start + 1
+	case expr[start].text == "(":
+		depth := 0
+		for i := start; i < len(expr); i++ {
+			if expr[i].text == "(" {
+				depth++
+			} else if expr[i].text == ")" {
+				depth--
+				if depth == 0 {
+					arrow = i + 1
+					break
+				}
+			}
+		}
+	}
+	if arrow > 0 && isArrow(expr, arrow) {
+		if isServerActionBody(expr, arrow) {
+			return ""
+		}
+		return "an inline function"
+	}
+	return ""
+}
+
+func isArrow(expr []token, i int) bool {
+	return tokenText(expr, i) == "=" && tokenText(expr, i+1) == ">" && expr[i+1].offset == expr[i].offset+1
+}
+
+func isServerActionBody(expr []token, from int) bool {
+	for i := from; i < len(expr)-1; i++ {
+		if expr[i].text == "{" {
+			body := expr[i+1]
+			return body.kind == tokenString && strings.Trim(body.text, `'"`) == "use server"
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+var (
+	serverDirectives        = []string{"'use server'", `"use server"`}
+	inlineServerActionRegex = regexp.MustCompile(`(?:function\s+(` + identPattern + `)\s*\([^)]*\)|(?:const|let|var)\s+(` + identPattern + `)\s*=\s*async\s*(?:\([^)]*\)|` + identPattern + `)\s*=>)\s*\{\s*['"]use server['"]`)
+)
+
+type serverActionImport struct {
+	Source   string
+	Resolved string
+	Line     int
+}
+
+func isServerActionFile(path string, config *Config) bool {
+	server := *config
+	server.Directives = serverDirectives
+	server.cache = nil
+	return isClientFile(path, &server)
+}
+
+func hasServerDirective(content []byte, config *Config) bool {
+	server := *config
+	server.Directives = serverDirectives
+	return hasDirective(bytes.NewReader(content), &server)
+}
+
+func findServerActions(filePath, text string, lines []string, imports map[string]serverActionImport, components map[string]clientImport, isClient bool) []Finding {
+	var findings []Finding
+
+	if isClient {
+		names := make([]string, 0, len(imports))
+		for name := range imports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			action := imports[name]
+			findings = append(findings, Finding{
+				File:               filePath,
+				Line:               action.Line,
+				Content:            lines[action.Line-1],
+				Rule:               RuleServerAction,
+				Severity:           SeverityWarning,
+				Message:            fmt.Sprintf("client file imports server action '%s' from '%s'", name, action.Source),
+				Component:          name,
+				ImportSource:       action.Source,
+				ResolvedClientFile: action.Resolved,
+			})
+
+			callRegex := regexp.MustCompile(`(?:^|[^.` + identPartChars + `])` + regexp.QuoteMeta(name) + `\s*\(|=\{\s*` + regexp.QuoteMeta(name) + `\s*\}`)
+			for i, line := range lines {
+				if i+1 == action.Line {
+					continue
+				}
+				if loc := callRegex.FindStringIndex(line); loc != nil {
+					findings = append(findings, Finding{
+						File:               filePath,
+						Line:               i + 1,
+						Column:             loc[0] + 1,
+						Content:            line,
+						Rule:               RuleServerAction,
+						Severity:           SeverityWarning,
+						Message:            fmt.Sprintf("client file invokes server action '%s' from '%s'", name, action.Source),
+						Component:          name,
+						ImportSource:       action.Source,
+						ResolvedClientFile: action.Resolved,
+					})
+				}
+			}
+		}
+		return findings
+	}
+
+	for _, match := range inlineServerActionRegex.FindAllStringSubmatch(text, -1) {
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+		propRegex := regexp.MustCompile(`=\{\s*` + regexp.QuoteMeta(name) + `\s*\}`)
+
+		for _, loc := range propRegex.FindAllStringIndex(text, -1) {
+			tags := jsxTagRegex.FindAllStringSubmatch(text[:loc[0]], -1)
+			if len(tags) == 0 {
+				continue
+			}
+			component := tags[len(tags)-1][1]
+			usage, ok := components[component]
+			if !ok {
+				continue
+			}
+
+			line := lineAt(text, loc[0])
+			findings = append(findings, Finding{
+				File:               filePath,
+				Line:               line,
+				Content:            lines[line-1],
+				Rule:               RuleServerAction,
+				Severity:           SeverityWarning,
+				Message:            fmt.Sprintf("inline server action '%s' passed to client component <%s>", name, component),
+				Component:          component,
+				ImportSource:       usage.Source,
+				ResolvedClientFile: usage.Resolved,
+			})
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,60 @@
+package main
+
+import "fmt"
+
+const SeverityOff = "off"
+
+func parseSeverity(level string) (string, error) {
+	switch level {
+	case "error":
+		return SeverityError, nil
+	case "warn", "warning":
+		return SeverityWarning, nil
+	case "off":
+		return SeverityOff, nil
+	default:
+		return "", fmt.Errorf("unknown severity '%s' (want error, warn or off)", level)
+	}
+}
+
+func parseRuleSeverities(rules map[string]string, boundaries []Boundary) (map[string]string, error) {
+	severities := make(map[string]string)
+	for rule, level := range rules {
+		if !isKnownRule(rule, boundaries) {
+			return nil, fmt.Errorf("rules: unknown rule '%s'", rule)
+		}
+		severity, err := parseSeverity(level)
+		if err != nil {
+			return nil, fmt.Errorf("rules: %s: %v", rule, err)
+		}
+		severities[rule] = severity
+	}
+	return severities, nil
+}
+
+func isKnownRule(rule string, boundaries []Boundary) bool {
+	for _, description := range ruleDescriptions {
+		if description.ID == rule {
+			return true
+		}
+	}
+	return containsString(boundaryNames(boundaries), rule)
+}
+
+func applySeverities(r *ScanResult, start int, severities map[string]string) {
+	if len(severities) == 0 {
+		return
+	}
+	kept := r.Findings[:start]
+	for _, f := range r.Findings[start:] {
+		switch severity, ok := severities[f.Rule]; {
+		case !ok:
+		case severity == SeverityOff:
+			continue
+		default:
+			f.Severity = severity
+		}
+		kept = append(kept, f)
+	}
+	r.Findings = kept
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type packageSideEffects struct {
+	Dir      string
+	Free     bool
+	Patterns []string
+}
+
+func loadPackageSideEffects(path string) *packageSideEffects {
+	pkg := &packageSideEffects{Dir: filepath.Dir(path)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return pkg
+	}
+
+	var manifest struct {
+		SideEffects json.RawMessage `json:"sideEffects"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil || manifest.SideEffects == nil {
+		return pkg
+	}
+
+	var flag bool
+	if err := json.Unmarshal(manifest.SideEffects, &flag); err == nil {
+		pkg.Free = !flag
+		return pkg
+	}
+	if err := json.Unmarshal(manifest.SideEffects, &pkg.Patterns); err == nil {
+		pkg.Free = true
+	}
+	return pkg
+}
+
+func (p *packageSideEffects) hasSideEffects(path string) bool {
+	if !p.Free {
+		return true
+	}
+
+	rel, err := filepath.Rel(p.Dir, path)
+	if err != nil {
+		return true
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range p.Patterns {
+		pattern = strings.TrimPrefix(pattern, "./")
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *ImportGraph) sideEffectFree(path string) bool {
+	manifest := findUp(filepath.Dir(path), "package.json")
+	if manifest == "" {
+		return false
+	}
+
+	if g.packages == nil {
+		g.packages = make(map[string]*packageSideEffects)
+	}
+	pkg, ok := g.packages[manifest]
+	if !ok {
+		pkg = loadPackageSideEffects(manifest)
+		g.packages[manifest] = pkg
+	}
+	return !pkg.hasSideEffects(path)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func filterSince(result *ScanResult, since string, roots []ScanRoot) error {
+	commit, err := sinceCommit(since)
+	if err != nil {
+		return err
+	}
+
+	renames, err := gitRenames(commit)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]map[string]bool)
+	var kept []Finding
+	for _, f := range result.Findings {
+		fingerprints, ok := previous[f.File]
+		if !ok {
+			fingerprints = previousFingerprints(commit, f.File, renames, roots)
+			previous[f.File] = fingerprints
+		}
+		if !fingerprints[f.Fingerprint] {
+			kept = append(kept, f)
+		}
+	}
+	result.Findings = kept
+
+	return nil
+}
+
+func sinceCommit(since string) (string, error) {
+	if out, err := exec.Command("git", "rev-parse", "--verify", "--quiet", since+"^{commit}").Output(); err == nil {
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	out, err := exec.Command("git", "rev-list", "-1", "--before="+since, "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-list: %w", err)
+	}
+	commit := strings.TrimSpace(string(out))
+	if commit == "" {
+		return "", fmt.Errorf("%s is neither a git revision nor a date with an earlier commit", since)
+	}
+	return commit, nil
+}
+
+func gitRenames(commit string) (map[string]string, error) {
+	out, err := exec.Command("git", "diff", "--name-status", "-M", "-z", "--relative", commit).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+
+	renames := make(map[string]string)
+	fields := strings.Split(string(out), "\x00")
+	for i := 0; i < len(fields); i++ {
+		status := fields[i]
+		switch {
+		case strings.HasPrefix(status, "R") || strings.HasPrefix(status, "C"):
+			if i+2 < len(fields) {
+				renames[fields[i+2]] = fields[i+1]
+			}
+			i += 2
+		case status != "":
+			i++
+		}
+	}
+	return renames, nil
+}
+
+func previousFingerprints(commit, file string, renames map[string]string, roots []ScanRoot) map[string]bool {
+	rel := file
+	if cwd, err := os.Getwd(); err == nil {
+		if r, err := filepath.Rel(cwd, absPath(file)); err == nil {
+			rel = r
+		}
+	}
+	rel = filepath.ToSlash(rel)
+	if old, ok := renames[rel]; ok {
+		rel = old
+	}
+
+	content, err := exec.Command("git", "show", commit+":./"+rel).Output()
+	if err != nil {
+		return nil
+	}
+
+	old := &ScanResult{}
+	if err := scanContent(file, content, rootConfig(roots, file), false, old); err != nil {
+		return nil
+	}
+
+	fingerprints := make(map[string]bool)
+	for _, f := range old.Findings {
+		fingerprints[f.Fingerprint] = true
+	}
+	return fingerprints
+}
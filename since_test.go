@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// chdir switches to dir for the rest of the test, since the git helpers run
+// in the working directory.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+}
+
+func gitFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFiles(t, root, files)
+	git(t, root, "init", "-q")
+	git(t, root, "add", "-A")
+	git(t, root, "commit", "-q", "-m", "initial")
+	chdir(t, root)
+	return root
+}
+
+func TestFilterSince(t *testing.T) {
+	gitFixture(t, map[string]string{
+		"Button.tsx": "'use client'\nexport function Button() { return null }\n",
+		"page.tsx":   "import { Button } from './Button'\nexport default function Page() { return <Button /> }\n",
+		"old.tsx":    "import { Button } from './Button'\nexport function Old() { return <Button /> }\n",
+	})
+	writeFiles(t, ".", map[string]string{
+		"page.tsx": "import { Button } from './Button'\nexport function More() { return <Button /> }\nexport default function Page() { return <Button /> }\n",
+	})
+	git(t, ".", "mv", "old.tsx", "renamed.tsx")
+
+	result := &ScanResult{}
+	config := DefaultConfig()
+	if err := scanPath(".", config, false, result); err != nil {
+		t.Fatal(err)
+	}
+	if err := filterSince(result, "HEAD", []ScanRoot{{Path: ".", Config: config}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, f := range result.Findings {
+		got = append(got, filepath.ToSlash(f.File)+":"+f.Content)
+	}
+	if want := []string{"page.tsx:export function More() { return <Button /> }"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("findings since HEAD = %v, want %v", got, want)
+	}
+
+	if err := filterSince(&ScanResult{}, "2000-01-01", nil); err == nil {
+		t.Error("filterSince() before the first commit succeeded, want an error")
+	}
+}
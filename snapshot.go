@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const defaultSnapshotFile = ".rscboundary-snapshot.json"
+
+type Snapshot struct {
+	Findings   []SnapshotFinding    `json:"findings"`
+	Unresolved []SnapshotUnresolved `json:"unresolved"`
+}
+
+type SnapshotFinding struct {
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Content     string `json:"content"`
+	Message     string `json:"message,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+type SnapshotUnresolved struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Source string `json:"source"`
+}
+
+func newSnapshot(result *ScanResult) *Snapshot {
+	snapshot := &Snapshot{
+		Findings:   []SnapshotFinding{},
+		Unresolved: []SnapshotUnresolved{},
+	}
+
+	for _, f := range result.Findings {
+		snapshot.Findings = append(snapshot.Findings, SnapshotFinding{
+			File:        filepath.ToSlash(f.File),
+			Line:        f.Line,
+			Rule:        f.Rule,
+			Severity:    f.Severity,
+			Content:     strings.TrimSpace(f.Content),
+			Message:     f.Message,
+			Fingerprint: f.Fingerprint,
+		})
+	}
+	for _, u := range result.Unresolved {
+		snapshot.Unresolved = append(snapshot.Unresolved, SnapshotUnresolved{
+			File:   filepath.ToSlash(u.File),
+			Line:   u.Line,
+			Source: u.Source,
+		})
+	}
+
+	sort.Slice(snapshot.Findings, func(i, j int) bool {
+		return snapshot.Findings[i].key() < snapshot.Findings[j].key()
+	})
+	sort.Slice(snapshot.Unresolved, func(i, j int) bool {
+		return snapshot.Unresolved[i].key() < snapshot.Unresolved[j].key()
+	})
+
+	return snapshot
+}
+
+func (f SnapshotFinding) key() string {
+	return fmt.Sprintf("%s:%08d:%s:%s:%s", f.File, f.Line, f.Rule, f.Content, f.Message)
+}
+
+func (f SnapshotFinding) String() string {
+	if f.Message != "" {
+		return fmt.Sprintf("%s:%d:%s: %s", f.File, f.Line, f.Severity, f.Message)
+	}
+	return fmt.Sprintf("%s:%d:%s", f.File, f.Line, f.Content)
+}
+
+func (u SnapshotUnresolved) key() string {
+	return fmt.Sprintf("%s:%08d:%s", u.File, u.Line, u.Source)
+}
+
+func (u SnapshotUnresolved) String() string {
+	return fmt.Sprintf("%s:%d: unresolved import '%s'", u.File, u.Line, u.Source)
+}
+
+func writeSnapshot(path string, snapshot *Snapshot) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshot); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+func readSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func verifySnapshot(w io.Writer, expected, actual *Snapshot) bool {
+	expectedKeys := make(map[string]string)
+	actualKeys := make(map[string]string)
+	for _, f := range expected.Findings {
+		expectedKeys[f.key()] = f.String()
+	}
+	for _, u := range expected.Unresolved {
+		expectedKeys[u.key()] = u.String()
+	}
+	for _, f := range actual.Findings {
+		actualKeys[f.key()] = f.String()
+	}
+	for _, u := range actual.Unresolved {
+		actualKeys[u.key()] = u.String()
+	}
+
+	var diff []string
+	for key, line := range expectedKeys {
+		if _, ok := actualKeys[key]; !ok {
+			diff = append(diff, key+"\x00-"+line)
+		}
+	}
+	for key, line := range actualKeys {
+		if _, ok := expectedKeys[key]; !ok {
+			diff = append(diff, key+"\x00+"+line)
+		}
+	}
+	sort.Strings(diff)
+
+	for _, entry := range diff {
+		fmt.Fprintln(w, entry[strings.IndexByte(entry, 0)+1:])
+	}
+	return len(diff) == 0
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+)
+
+// maxMessageBytes caps the Content-Length a client may announce so a bad
+// header cannot make readMessage allocate without bound.
+const maxMessageBytes = 32 << 20
+
+type serverRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		Path string `json:"path"`
+	} `json:"params"`
+}
+
+type serverResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type clientStatus struct {
+	Path      string `json:"path"`
+	IsClient  bool   `json:"isClient"`
+	Directive bool   `json:"directive"`
+}
+
+type boundaryChainResult struct {
+	Path  string   `json:"path"`
+	Chain []string `json:"chain"`
+}
+
+func runStdioServer(roots []ScanRoot, verbose bool, in io.Reader, out io.Writer) error {
+	graph, err := buildImportGraph(roots, verbose)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		data, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req serverRequest
+		resp := serverResponse{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+			if err := writeMessage(out, resp); err != nil {
+				return err
+			}
+			continue
+		}
+		resp.ID = req.ID
+
+		switch req.Method {
+		case "isClient":
+			node := graph.load(req.Params.Path, roots[0].Config, verbose)
+			resp.Result = clientStatus{
+				Path:      node.Path,
+				IsClient:  graph.boundaryChain(node.Path) != nil,
+				Directive: node.IsClient,
+			}
+		case "boundaryChain":
+			node := graph.load(req.Params.Path, roots[0].Config, verbose)
+			chain := graph.boundaryChain(node.Path)
+			if chain == nil {
+				chain = []string{}
+			}
+			resp.Result = boundaryChainResult{Path: node.Path, Chain: chain}
+		case "reload":
+			graph, err = buildImportGraph(roots, verbose)
+			if err != nil {
+				return err
+			}
+			resp.Result = map[string]int{"files": len(graph.Nodes)}
+		case "shutdown":
+			return writeMessage(out, resp)
+		default:
+			resp.Error = fmt.Sprintf("unknown method: %s", req.Method)
+		}
+
+		if err := writeMessage(out, resp); err != nil {
+			return err
+		}
+	}
+}
+
+func readMessage(reader *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	value := header.Get("Content-Length")
+	if value == "" {
+		return nil, errors.New("missing Content-Length header")
+	}
+	length, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %w", err)
+	}
+	if length < 0 || length > maxMessageBytes {
+		return nil, fmt.Errorf("invalid Content-Length %d: must be between 0 and %d", length, maxMessageBytes)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func writeMessage(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		err   bool
+	}{
+		{name: "valid", input: "Content-Length: 2\r\n\r\n{}", want: "{}"},
+		{name: "extra headers", input: "Content-Type: application/json\r\nContent-Length: 2\r\n\r\n{}", want: "{}"},
+		{name: "empty body", input: "Content-Length: 0\r\n\r\n", want: ""},
+		{name: "missing length", input: "Content-Type: application/json\r\n\r\n{}", err: true},
+		{name: "non-numeric length", input: "Content-Length: two\r\n\r\n{}", err: true},
+		{name: "negative length", input: "Content-Length: -1\r\n\r\n{}", err: true},
+		{name: "oversized length", input: "Content-Length: 99999999999\r\n\r\n{}", err: true},
+		{name: "truncated body", input: "Content-Length: 10\r\n\r\n{}", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := readMessage(bufio.NewReader(strings.NewReader(tt.input)))
+			if (err != nil) != tt.err {
+				t.Fatalf("readMessage() error = %v, want error %v", err, tt.err)
+			}
+			if !tt.err && string(data) != tt.want {
+				t.Errorf("readMessage() = %q, want %q", data, tt.want)
+			}
+		})
+	}
+
+	if _, err := readMessage(bufio.NewReader(strings.NewReader(""))); !errors.Is(err, io.EOF) {
+		t.Errorf("readMessage() at end of input = %v, want io.EOF", err)
+	}
+}
+
+func frame(t *testing.T, v interface{}) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := writeMessage(&buf, v); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func readResponses(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var responses []map[string]interface{}
+	reader := bufio.NewReader(out)
+	for {
+		data, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return responses
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			t.Fatal(err)
+		}
+		responses = append(responses, resp)
+	}
+}
+
+func TestStdioServer(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"Button.tsx": "'use client'\nexport function Button() { return null }\n",
+		"page.tsx":   "import { Button } from './Button'\nexport default function P() { return <Button /> }\n",
+	})
+	roots := []ScanRoot{{Path: root, Config: DefaultConfig()}}
+	button := filepath.Join(root, "Button.tsx")
+
+	input := frame(t, map[string]interface{}{"id": 1, "method": "isClient", "params": map[string]string{"path": button}}) +
+		frame(t, map[string]interface{}{"id": 2, "method": "nope"}) +
+		frame(t, map[string]interface{}{"id": 3, "method": "shutdown"})
+	var out bytes.Buffer
+	if err := runStdioServer(roots, false, strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := readResponses(t, &out)
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want 3", len(responses))
+	}
+	if result, _ := responses[0]["result"].(map[string]interface{}); result["isClient"] != true || result["directive"] != true {
+		t.Errorf("isClient response = %v, want a client file with a directive", responses[0])
+	}
+	if responses[1]["error"] != "unknown method: nope" {
+		t.Errorf("unknown method response = %v", responses[1])
+	}
+}
+
+func TestStdioServerRejectsBadContentLength(t *testing.T) {
+	roots := []ScanRoot{{Path: t.TempDir(), Config: DefaultConfig()}}
+	for _, header := range []string{"Content-Length: -5", "Content-Length: 1099511627776", "X-Other: 1"} {
+		var out bytes.Buffer
+		err := runStdioServer(roots, false, strings.NewReader(header+"\r\n\r\n{}"), &out)
+		if err == nil || !strings.Contains(err.Error(), "Content-Length") {
+			t.Errorf("runStdioServer() with %q returned %v, want a Content-Length error", header, err)
+		}
+	}
+}
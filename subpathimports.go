@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type subpathImportsMemo struct {
+	mu        sync.Mutex
+	manifests map[string]string
+	imports   map[string]json.RawMessage
+}
+
+func newSubpathImportsMemo() *subpathImportsMemo {
+	return &subpathImportsMemo{manifests: make(map[string]string), imports: make(map[string]json.RawMessage)}
+}
+
+func (m *subpathImportsMemo) lookup(baseDir string) (string, json.RawMessage) {
+	dir := absPath(baseDir)
+	if m == nil {
+		manifestPath := findUp(dir, "package.json")
+		return manifestPath, loadSubpathImports(manifestPath)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	manifestPath, ok := m.manifests[dir]
+	if !ok {
+		manifestPath = findUp(dir, "package.json")
+		m.manifests[dir] = manifestPath
+	}
+	if manifestPath == "" {
+		return "", nil
+	}
+
+	imports, ok := m.imports[manifestPath]
+	if !ok {
+		imports = loadSubpathImports(manifestPath)
+		m.imports[manifestPath] = imports
+	}
+	return manifestPath, imports
+}
+
+func (m *subpathImportsMemo) forget() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.manifests = make(map[string]string)
+	m.imports = make(map[string]json.RawMessage)
+}
+
+func loadSubpathImports(manifestPath string) json.RawMessage {
+	if manifestPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil
+	}
+	var manifest struct {
+		Imports json.RawMessage `json:"imports"`
+	}
+	json.Unmarshal(data, &manifest)
+	return manifest.Imports
+}
+
+func resolveSubpathImport(baseDir, specifier string, config *Config) (Resolution, bool) {
+	var resolution Resolution
+	manifestPath, imports := config.subpathImports.lookup(baseDir)
+	config.dependsOn(manifestPath)
+	config.dependsOnProbes(absPath(baseDir), manifestPath, "package.json")
+	if imports == nil {
+		explainf(config, "  no package.json \"imports\" for '%s'", specifier)
+		return resolution, false
+	}
+
+	target, ok := resolvePackageImports(imports, specifier)
+	if !ok {
+		explainf(config, "  %s: '%s' not in \"imports\"", manifestPath, specifier)
+		return resolution, false
+	}
+	explainf(config, "  %s imports '%s' -> %s", manifestPath, specifier, target)
+
+	if strings.HasPrefix(target, ".") {
+		resolution.Local = true
+		resolution.expand(filepath.Join(filepath.Dir(manifestPath), target), config)
+	} else if config.IncludeNodeModules && isPackageSpecifier(target) {
+		resolution.Paths = resolveNodeModule(filepath.Dir(manifestPath), target, config)
+	}
+	return resolution, true
+}
+
+func resolvePackageImports(imports json.RawMessage, specifier string) (string, bool) {
+	members, _ := jsonObjectMembers(imports)
+	return resolvePatternMembers(members, specifier)
+}
+
+func externalSpecifier(baseDir, specifier string, config *Config) (string, bool) {
+	if strings.HasPrefix(specifier, "#") {
+		_, imports := config.subpathImports.lookup(baseDir)
+		target, ok := resolvePackageImports(imports, specifier)
+		if !ok {
+			return "", false
+		}
+		specifier = target
+	}
+	return specifier, isPackageSpecifier(specifier)
+}
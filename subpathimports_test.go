@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestResolvePackageImports(t *testing.T) {
+	imports := []string{
+		`{"#*": "./src/*", "#lib/*": "./lib/*.ts", "#lib/internal/*": "./internal/*.ts", "#lib/db": "./db.ts", "#*.css": "./styles/*.css"}`,
+		`{"#lib/db": "./db.ts", "#*.css": "./styles/*.css", "#lib/internal/*": "./internal/*.ts", "#lib/*": "./lib/*.ts", "#*": "./src/*"}`,
+	}
+	tests := []struct {
+		specifier string
+		want      string
+		ok        bool
+	}{
+		{"#lib/db", "./db.ts", true},
+		{"#lib/internal/cache", "./internal/cache.ts", true},
+		{"#lib/auth", "./lib/auth.ts", true},
+		{"#lib/theme.css", "./lib/theme.css.ts", true},
+		{"#ui/theme.css", "./styles/ui/theme.css", true},
+		{"#ui/Button", "./src/ui/Button", true},
+		{"@ui/Button", "", false},
+	}
+
+	for _, raw := range imports {
+		for _, tt := range tests {
+			got, ok := resolvePackageImports(json.RawMessage(raw), tt.specifier)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("resolvePackageImports(%s, %q) = %q, %v, want %q, %v", raw, tt.specifier, got, ok, tt.want, tt.ok)
+			}
+		}
+	}
+}
+
+func TestResolvePackageExportsPatterns(t *testing.T) {
+	exports := json.RawMessage(`{".": "./index.js", "./*": "./dist/*.js", "./icons/*": "./icons/*.svg.js"}`)
+	tests := map[string]string{
+		".":          "./index.js",
+		"./Button":   "./dist/Button.js",
+		"./icons/up": "./icons/up.svg.js",
+	}
+	for subpath, want := range tests {
+		if got, ok := resolvePackageExports(exports, subpath); !ok || got != want {
+			t.Errorf("resolvePackageExports(%q) = %q, %v, want %q", subpath, got, ok, want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+func annotateClientSubtrees(result *ScanResult, roots []ScanRoot, verbose bool) {
+	graph := &ImportGraph{Nodes: make(map[string]*GraphNode)}
+	sizes := make(map[string]*SubtreeSize)
+
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		if f.ResolvedClientFile == "" {
+			continue
+		}
+
+		path := absPath(f.ResolvedClientFile)
+		size, ok := sizes[path]
+		if !ok {
+			size = &SubtreeSize{}
+			for _, node := range graph.closure(path, rootConfig(roots, f.File), verbose) {
+				size.Files++
+				size.Lines += node.Lines
+				size.Bytes += node.Size
+			}
+			sizes[path] = size
+		}
+		f.ClientSubtree = size
+	}
+}
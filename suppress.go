@@ -0,0 +1,91 @@
+package main
+
+import "strings"
+
+const (
+	disableFilePragma    = "rsc-boundary-disable-file"
+	ignoreLinePragma     = "rsc-boundary-ignore"
+	ignoreNextLinePragma = "rsc-boundary-ignore-next-line"
+)
+
+func fileSuppression(content []byte, config *Config) ([]string, bool) {
+	if int64(len(content)) > config.MaxReadBytes {
+		content = content[:config.MaxReadBytes]
+	}
+
+	for _, line := range splitLines(string(content)) {
+		if rules, ok := pragmaRules(line, disableFilePragma); ok {
+			return rules, true
+		}
+	}
+
+	return nil, false
+}
+
+func lineSuppressions(lines []string) map[int][]string {
+	suppressions := make(map[int][]string)
+	for i, line := range lines {
+		if !strings.Contains(line, ignoreLinePragma) {
+			continue
+		}
+		if rules, ok := pragmaRules(line, ignoreNextLinePragma); ok {
+			suppressions[i+2] = rules
+		} else if rules, ok := pragmaRules(line, ignoreLinePragma); ok {
+			suppressions[i+1] = rules
+		}
+	}
+	return suppressions
+}
+
+func pragmaRules(line, pragma string) ([]string, bool) {
+	idx := strings.Index(line, pragma)
+	if idx < 0 {
+		return nil, false
+	}
+	prefix := strings.TrimSpace(line[:idx])
+	if !strings.HasSuffix(prefix, "//") && !strings.HasSuffix(prefix, "/*") && !strings.HasPrefix(prefix, "*") {
+		return nil, false
+	}
+
+	rest := line[idx+len(pragma):]
+	if end := strings.Index(rest, "*/"); end >= 0 {
+		rest = rest[:end]
+	}
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return nil, false
+	}
+	return strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}), true
+}
+
+func suppressFindings(r *ScanResult, start int, rules []string) {
+	kept := r.Findings[:start]
+	for _, f := range r.Findings[start:] {
+		if len(rules) > 0 && !containsString(rules, f.Rule) {
+			kept = append(kept, f)
+			continue
+		}
+		if r.Suppressed == nil {
+			r.Suppressed = make(map[string]int)
+		}
+		r.Suppressed[f.Rule]++
+	}
+	r.Findings = kept
+}
+
+func suppressLines(r *ScanResult, start int, suppressions map[int][]string) {
+	kept := r.Findings[:start]
+	for _, f := range r.Findings[start:] {
+		rules, ok := suppressions[f.Line]
+		if !ok || (len(rules) > 0 && !containsString(rules, f.Rule)) {
+			kept = append(kept, f)
+			continue
+		}
+		if r.Suppressed == nil {
+			r.Suppressed = make(map[string]int)
+		}
+		r.Suppressed[f.Rule]++
+	}
+	r.Findings = kept
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func canonicalPath(path string) string {
+	abs := absPath(path)
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
+
+func directoryDepth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(filepath.ToSlash(rel), "/") + 1
+}
+
+func walkTree(root string, fn filepath.WalkFunc) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	err = walkEntry(root, info, make(map[string]bool), fn)
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+func walkEntry(path string, info os.FileInfo, visited map[string]bool, fn filepath.WalkFunc) error {
+	canonical := canonicalPath(path)
+	if visited[canonical] {
+		return nil
+	}
+	visited[canonical] = true
+
+	if err := fn(path, info, nil); err != nil || !info.IsDir() {
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return fn(path, info, err)
+	}
+	for _, entry := range entries {
+		child := filepath.Join(path, entry.Name())
+		childInfo, err := os.Stat(child)
+		if err != nil {
+			if entry.Type()&os.ModeSymlink != 0 {
+				continue
+			}
+			if err := fn(child, nil, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := walkEntry(child, childInfo, visited, fn); err != nil {
+			if err == filepath.SkipDir && childInfo.IsDir() {
+				continue
+			}
+			if err == filepath.SkipDir {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	phaseWalk = iota
+	phaseParse
+	phaseResolve
+	phaseDirectives
+	phaseReport
+	phaseCount
+)
+
+var phaseNames = [phaseCount]string{"walk", "parse", "resolve", "directive-check", "report"}
+
+type scanTimings struct {
+	start  time.Time
+	phases [phaseCount]int64
+	files  int64
+}
+
+func newScanTimings() *scanTimings {
+	return &scanTimings{start: time.Now()}
+}
+
+func (t *scanTimings) add(phase int, start time.Time) {
+	if t != nil {
+		atomic.AddInt64(&t.phases[phase], int64(time.Since(start)))
+	}
+}
+
+func (t *scanTimings) write(w io.Writer, jobs int) {
+	fmt.Fprintf(w, "Timings for %d files (parse, resolve and directive-check are summed across %d jobs):\n", atomic.LoadInt64(&t.files), jobs)
+	for phase, name := range phaseNames {
+		fmt.Fprintf(w, "  %-16s %s\n", name, time.Duration(atomic.LoadInt64(&t.phases[phase])).Round(time.Millisecond))
+	}
+	fmt.Fprintf(w, "  %-16s %s\n", "total", time.Since(t.start).Round(time.Millisecond))
+}
+
+type progressMeter struct {
+	mu      sync.Mutex
+	total   int
+	done    int
+	printed time.Time
+}
+
+func (p *progressMeter) expect(n int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.total += n
+	p.mu.Unlock()
+}
+
+func (p *progressMeter) step() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if p.done == p.total || time.Since(p.printed) >= 100*time.Millisecond {
+		fmt.Fprintf(os.Stderr, "\rScanned %d/%d files", p.done, p.total)
+		p.printed = time.Now()
+	}
+}
+
+func (p *progressMeter) finish() {
+	if p != nil && p.total > 0 {
+		fmt.Fprint(os.Stderr, "\r\x1b[K")
+	}
+}
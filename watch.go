@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const watchDebounce = 100 * time.Millisecond
+
+type watchState struct {
+	roots   []ScanRoot
+	verbose bool
+	files   map[string]*ScanResult
+}
+
+func runWatch(roots []ScanRoot, format string, verbose bool, w io.Writer) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	for _, root := range roots {
+		if err := watchTree(watcher, root.Path, root.Config); err != nil {
+			return err
+		}
+	}
+
+	state := &watchState{roots: roots, verbose: verbose, files: make(map[string]*ScanResult)}
+	rescanned := state.rescan(nil)
+	if err := writeReport(w, format, state.result()); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Watching for changes (%d files scanned)\n", rescanned)
+
+	return watchEvents(watcher, roots, func(changed map[string]bool, structural bool) error {
+		dirty := state.dependents(changed, structural)
+		rescanned := state.rescan(dirty)
+		fmt.Fprintf(os.Stderr, "--- %s: %d changed, %d files rescanned\n", time.Now().Format("15:04:05"), len(changed), rescanned)
+		return writeReport(w, format, state.result())
+	})
+}
+
+func watchEvents(watcher *fsnotify.Watcher, roots []ScanRoot, apply func(changed map[string]bool, structural bool) error) error {
+	changed := make(map[string]bool)
+	structural := false
+	var timer <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					for _, root := range roots {
+						if isWithin(absPath(event.Name), absPath(root.Path)) {
+							watchTree(watcher, event.Name, root.Config)
+						}
+					}
+				}
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
+				structural = true
+			}
+			changed[absPath(event.Name)] = true
+			timer = time.After(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Warning: watch: %v\n", err)
+		case <-timer:
+			timer = nil
+			if err := apply(changed, structural); err != nil {
+				return err
+			}
+			changed = make(map[string]bool)
+			structural = false
+		}
+	}
+}
+
+func watchTree(watcher *fsnotify.Watcher, dir string, config *Config) error {
+	return walkTree(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != dir && (name == "node_modules" || name == ".git" || name == "dist" || name == "build") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+func (s *watchState) dependents(changed map[string]bool, structural bool) map[string]bool {
+	dirty := make(map[string]bool)
+	for path := range changed {
+		dirty[path] = true
+	}
+
+	for _, root := range s.roots {
+		root.Config.cache.forgetHashes()
+	}
+
+	projectChanged := false
+	for path := range changed {
+		projectChanged = projectChanged || isProjectConfigFile(path) || filepath.Base(path) == "package.json"
+		for _, root := range s.roots {
+			projectChanged = projectChanged || root.Config.importMaps.covers(path)
+		}
+	}
+	if structural || projectChanged {
+		for _, root := range s.roots {
+			root.Config.projects.forget()
+			root.Config.subpathImports.forget()
+			root.Config.importMaps.forget()
+			root.Config.listings.forget()
+		}
+	}
+	if projectChanged {
+		for path := range s.files {
+			dirty[path] = true
+		}
+		return dirty
+	}
+
+	for path, result := range s.files {
+		if structural && len(result.Unresolved) > 0 {
+			dirty[path] = true
+			continue
+		}
+		for _, resolved := range result.Resolved {
+			if changed[absPath(resolved)] {
+				dirty[path] = true
+				break
+			}
+		}
+	}
+	return dirty
+}
+
+func (s *watchState) rescan(dirty map[string]bool) int {
+	seen := make(map[string]bool)
+	rescanned := 0
+
+	for _, root := range s.roots {
+		config := root.Config
+		walkSourceFiles(root.Path, config, func(path string) {
+			abs := absPath(path)
+			seen[abs] = true
+			if _, ok := s.files[abs]; ok && !dirty[abs] {
+				return
+			}
+
+			result := &ScanResult{}
+			if err := scanFile(path, config, s.verbose, result); err != nil && s.verbose {
+				fmt.Fprintf(os.Stderr, "Warning: failed to scan %s: %v\n", path, err)
+			}
+			s.files[abs] = result
+			rescanned++
+		})
+	}
+
+	for path := range s.files {
+		if !seen[path] {
+			delete(s.files, path)
+		}
+	}
+	return rescanned
+}
+
+func (s *watchState) result() *ScanResult {
+	result := &ScanResult{}
+	for _, root := range s.roots {
+		walkSourceFiles(root.Path, root.Config, func(path string) {
+			if file, ok := s.files[absPath(path)]; ok {
+				result.Merge(file)
+			}
+		})
+	}
+	result.Resolved = nil
+	return result
+}
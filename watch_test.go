@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestWatchStateRescan(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"Button.tsx": "'use client'\nexport function Button() { return null }\n",
+		"Card.tsx":   "'use client'\nexport function Card() { return null }\n",
+		"page.tsx":   "import { Button } from './Button'\nimport { Panel } from './Panel'\nexport default function Page() { return <><Button /><Panel /></> }\n",
+		"about.tsx":  "import { Card } from './Card'\nexport default function About() { return <Card /> }\n",
+	})
+	abs := func(name string) string {
+		return absPath(filepath.Join(root, name))
+	}
+	names := func(paths map[string]bool) []string {
+		var names []string
+		for path := range paths {
+			names = append(names, filepath.Base(path))
+		}
+		sort.Strings(names)
+		return names
+	}
+	components := func(s *watchState) []string {
+		var components []string
+		for _, f := range s.result().Findings {
+			components = append(components, f.Component)
+		}
+		sort.Strings(components)
+		return components
+	}
+
+	state := &watchState{roots: []ScanRoot{{Path: root, Config: DefaultConfig()}}, files: make(map[string]*ScanResult)}
+	if n := state.rescan(nil); n != 4 {
+		t.Fatalf("initial scan covered %d files, want 4", n)
+	}
+	if got, want := components(state), []string{"Button", "Card"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("initial findings for %v, want %v", got, want)
+	}
+
+	writeFiles(t, root, map[string]string{"Button.tsx": "export function Button() { return null }\n"})
+	dirty := state.dependents(map[string]bool{abs("Button.tsx"): true}, false)
+	if got, want := names(dirty), []string{"Button.tsx", "page.tsx"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dependents of Button.tsx = %v, want %v", got, want)
+	}
+	if n := state.rescan(dirty); n != 2 {
+		t.Errorf("rescanned %d files, want 2", n)
+	}
+	if got, want := components(state), []string{"Card"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("findings after removing the directive for %v, want %v", got, want)
+	}
+
+	writeFiles(t, root, map[string]string{"Panel.tsx": "'use client'\nexport function Panel() { return null }\n"})
+	dirty = state.dependents(map[string]bool{abs("Panel.tsx"): true}, true)
+	if got, want := names(dirty), []string{"Panel.tsx", "page.tsx"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dependents of a new file = %v, want %v", got, want)
+	}
+	state.rescan(dirty)
+	if got, want := components(state), []string{"Card", "Panel"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("findings after adding Panel.tsx for %v, want %v", got, want)
+	}
+
+	writeFiles(t, root, map[string]string{"tsconfig.json": "{}"})
+	dirty = state.dependents(map[string]bool{abs("tsconfig.json"): true}, true)
+	if got, want := names(dirty), []string{"Button.tsx", "Card.tsx", "Panel.tsx", "about.tsx", "page.tsx", "tsconfig.json"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dependents of tsconfig.json = %v, want %v", got, want)
+	}
+
+	if err := os.Remove(filepath.Join(root, "about.tsx")); err != nil {
+		t.Fatal(err)
+	}
+	state.rescan(state.dependents(map[string]bool{abs("about.tsx"): true}, true))
+	if _, ok := state.files[abs("about.tsx")]; ok {
+		t.Error("deleted about.tsx is still tracked")
+	}
+	if got, want := components(state), []string{"Panel"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("findings after deleting about.tsx for %v, want %v", got, want)
+	}
+}
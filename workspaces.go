@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+type workspaceMemo struct {
+	mu       sync.Mutex
+	roots    map[string]string
+	packages map[string]map[string]string
+}
+
+type workspaceManifest struct {
+	Name       string          `json:"name"`
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+func newWorkspaceMemo() *workspaceMemo {
+	return &workspaceMemo{roots: make(map[string]string), packages: make(map[string]map[string]string)}
+}
+
+func (m *workspaceMemo) lookup(baseDir, name string) (string, bool) {
+	dir := absPath(baseDir)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	root, ok := m.roots[dir]
+	if !ok {
+		root = findWorkspaceRoot(dir)
+		m.roots[dir] = root
+	}
+	if root == "" {
+		return "", false
+	}
+
+	packages, ok := m.packages[root]
+	if !ok {
+		packages = loadWorkspacePackages(root)
+		m.packages[root] = packages
+	}
+	packageDir, ok := packages[name]
+	return packageDir, ok
+}
+
+func findWorkspaceRoot(dir string) string {
+	for {
+		if len(workspacePatterns(dir)) > 0 {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func workspacePatterns(dir string) []string {
+	if data, err := os.ReadFile(filepath.Join(dir, "pnpm-workspace.yaml")); err == nil {
+		var file struct {
+			Packages []string `yaml:"packages"`
+		}
+		if yaml.Unmarshal(data, &file) == nil {
+			return file.Packages
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var manifest workspaceManifest
+	if json.Unmarshal(data, &manifest) != nil || manifest.Workspaces == nil {
+		return nil
+	}
+
+	var patterns []string
+	if json.Unmarshal(manifest.Workspaces, &patterns) == nil {
+		return patterns
+	}
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	json.Unmarshal(manifest.Workspaces, &object)
+	return object.Packages
+}
+
+func loadWorkspacePackages(root string) map[string]string {
+	var include, exclude []string
+	for _, pattern := range workspacePatterns(root) {
+		pattern = strings.TrimPrefix(strings.TrimSuffix(pattern, "/"), "./")
+		if negated, ok := strings.CutPrefix(pattern, "!"); ok {
+			exclude = append(exclude, negated)
+		} else {
+			include = append(include, pattern)
+		}
+	}
+
+	packages := make(map[string]string)
+	walkTree(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if name := info.Name(); path != root && (name == "node_modules" || name == ".git") {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if !matchesAny(include, rel) || matchesAny(exclude, rel) {
+			return nil
+		}
+
+		data, err := os.ReadFile(filepath.Join(path, "package.json"))
+		if err != nil {
+			return nil
+		}
+		var manifest workspaceManifest
+		if json.Unmarshal(data, &manifest) == nil && manifest.Name != "" {
+			packages[manifest.Name] = path
+		}
+		return nil
+	})
+	return packages
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveWorkspacePackage(dir, specifier string, config *Config) []string {
+	manifestPath := filepath.Join(dir, "package.json")
+	var manifest packageManifest
+	if data, err := os.ReadFile(manifestPath); err == nil {
+		json.Unmarshal(data, &manifest)
+	}
+
+	subpath := "." + strings.TrimPrefix(specifier, packageName(specifier))
+	var targets []string
+	if manifest.Exports != nil {
+		if target, ok := resolvePackageExports(manifest.Exports, subpath); ok {
+			explainf(config, "  %s exports '%s' -> %s", manifestPath, subpath, target)
+			targets = append(targets, target)
+		}
+	}
+	if subpath != "." {
+		targets = append(targets, subpath, filepath.Join("src", subpath))
+	} else {
+		targets = append(targets, manifest.Source, manifest.Module, manifest.Main, "src/index", "index")
+	}
+
+	for _, target := range targets {
+		if target == "" {
+			continue
+		}
+		for _, candidate := range sourceCandidates(target) {
+			if paths, _ := expandPath(filepath.Join(dir, candidate), config); len(paths) > 0 {
+				return paths
+			}
+		}
+	}
+	return nil
+}
+
+func sourceCandidates(target string) []string {
+	candidates := []string{target}
+	for _, output := range []string{"dist/", "build/", "lib/", "out/"} {
+		if rest, ok := strings.CutPrefix(strings.TrimPrefix(target, "./"), output); ok {
+			candidates = append(candidates, "src/"+rest)
+		}
+	}
+	return candidates
+}